@@ -27,7 +27,8 @@ func DefaultParameters() *Parameters {
 	}
 }
 
-// Bundle
+// Bundle provides HTTP server. Use slice.Instance() to run several servers, e.g. public and admin
+// servers with PUBLIC_ADDR and ADMIN_ADDR parameters.
 var Bundle = bundle.New(
 	bundle.WithName("http"),
 	bundle.WithParameters(
@@ -14,7 +14,8 @@ func main() {
 	slice.Run(
 		slice.WithName("bundle-app"),
 		slice.WithBundles(
-			httpsrv.Bundle,
+			slice.Instance(httpsrv.Bundle, "public"),
+			slice.Instance(httpsrv.Bundle, "admin"),
 		),
 		slice.WithComponents(
 			slice.Provide(NewDispatcher, di.As(new(slice.Dispatcher))),
@@ -22,26 +23,37 @@ func main() {
 	)
 }
 
+// Servers contains public and admin servers of httpsrv bundle instances.
+type Servers struct {
+	di.Inject
+	Public *http.Server `di:"name=public"`
+	Admin  *http.Server `di:"name=admin"`
+}
+
 type Dispatcher struct {
-	server *http.Server
+	servers []*http.Server
 }
 
-func NewDispatcher(server *http.Server) *Dispatcher {
-	return &Dispatcher{server: server}
+func NewDispatcher(servers Servers) *Dispatcher {
+	return &Dispatcher{servers: []*http.Server{servers.Public, servers.Admin}}
 }
 
 func (d Dispatcher) Run(ctx context.Context) (err error) {
-	errch := make(chan error)
-	go func() {
-		errch <- d.server.ListenAndServe()
-	}()
+	errch := make(chan error, len(d.servers))
+	for _, server := range d.servers {
+		go func(server *http.Server) {
+			errch <- server.ListenAndServe()
+		}(server)
+	}
 	select {
 	case <-ctx.Done():
-		if err := d.server.Close(); err != nil {
-			return err
+		for _, server := range d.servers {
+			if err := server.Close(); err != nil {
+				return err
+			}
 		}
 		return ctx.Err()
-	case <-errch:
+	case err = <-errch:
 		return err
 	}
 }
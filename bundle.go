@@ -2,21 +2,67 @@ package slice
 
 import (
 	"fmt"
+
+	"github.com/goava/di"
 )
 
 // A Bundle  is a separate unit of application.
 type Bundle struct {
-	Name       string
-	Parameters []Parameter
-	Components []ComponentOption
-	Hooks      []Hook
-	Bundles    []Bundle
+	Name string
+	// Description, Version, Owner and URL are optional bundle metadata. They are displayed
+	// with -bundles flag.
+	Description string
+	Version     string
+	Owner       string
+	URL         string
+	Parameters  []Parameter
+	Components  []ComponentOption
+	Hooks       []Hook
+	Checks      []Check
+	Bundles     []Bundle
+	// After contains names of bundles that should boot before this bundle if they are present
+	// in the application. Unlike Bundles, they are not added to the application.
+	After []string
+	// Requires contains pointers to types that bundle expects from other bundles or application,
+	// e.g. new(http.Handler). Requirements are checked on start.
+	Requires []di.Pointer
+	// Provides contains pointers to types that bundle components provide, e.g. new(*http.Server).
+	// Declarations are checked on start.
+	Provides []di.Pointer
+	// Constraints restricts versions of other bundles, e.g. {Bundle: "session", Version: ">= 1.3"}.
+	// Constraints are checked on start.
+	Constraints []Constraint
+	// Optional marks bundle as optional. Boot failure of optional bundle will be logged and reported
+	// through Health instead of aborting application start.
+	Optional bool
+	// Condition enables bundle only if it's satisfied. Bundle without condition always enabled.
+	Condition Condition
+
+	// instance name of bundle, see slice.Instance()
+	instance string
+	// container of bundle with private components
+	container *di.Container
+}
+
+// scope returns bundle container if bundle has private components, otherwise application container.
+func (b Bundle) scope(container *di.Container) *di.Container {
+	if b.container != nil {
+		return b.container
+	}
+	return container
+}
+
+// enabled checks bundle condition.
+func (b Bundle) enabled(info Info) bool {
+	return b.Condition == nil || b.Condition(info)
 }
 
 func (b Bundle) apply(app *Application) {
+	app.bundle = b.Name
 	for _, option := range b.Components {
 		option.apply(app)
 	}
+	app.bundle = ""
 }
 
 type startErrors []error
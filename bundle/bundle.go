@@ -1,6 +1,8 @@
 package bundle
 
 import (
+	"github.com/goava/di"
+
 	"github.com/goava/slice"
 )
 
@@ -25,6 +27,34 @@ func WithName(name string) Option {
 	})
 }
 
+// WithDescription sets bundle description.
+func WithDescription(description string) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Description = description
+	})
+}
+
+// WithVersion sets bundle version.
+func WithVersion(version string) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Version = version
+	})
+}
+
+// WithOwner sets bundle owner, e.g. team name.
+func WithOwner(owner string) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Owner = owner
+	})
+}
+
+// WithURL sets bundle documentation URL.
+func WithURL(url string) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.URL = url
+	})
+}
+
 // WithParameters add parameters to bundle.
 func WithParameters(parameters ...slice.Parameter) Option {
 	return option(func(bundle *slice.Bundle) {
@@ -46,6 +76,13 @@ func WithHooks(hooks ...slice.Hook) Option {
 	})
 }
 
+// WithChecks adds preflight checks.
+func WithChecks(checks ...slice.Check) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Checks = append(bundle.Checks, checks...)
+	})
+}
+
 // WithBundles add dependency bundle.
 func WithBundles(bundles ...slice.Bundle) Option {
 	return option(func(bundle *slice.Bundle) {
@@ -53,6 +90,57 @@ func WithBundles(bundles ...slice.Bundle) Option {
 	})
 }
 
+// Requires declares types that bundle expects from other bundles or application. Requirements are
+// checked on application start.
+//
+//	bundle.Requires(new(*sql.DB), new(slice.Logger))
+func Requires(types ...di.Pointer) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Requires = append(bundle.Requires, types...)
+	})
+}
+
+// Provides declares types that bundle components provide. Declarations are checked on application start.
+//
+//	bundle.Provides(new(*http.Server), new(http.Handler))
+func Provides(types ...di.Pointer) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Provides = append(bundle.Provides, types...)
+	})
+}
+
+// WithConstraint restricts version of another bundle, e.g. bundle.WithConstraint("session", ">= 1.3").
+// Constraint is a comma-separated list of comparisons with operators =, !=, >, >=, <, <=.
+func WithConstraint(name string, version string) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Constraints = append(bundle.Constraints, slice.Constraint{Bundle: name, Version: version})
+	})
+}
+
+// After orders bundle after named bundles if they are present in the application. Unlike
+// WithBundles(), it does not add them to the application.
+func After(names ...string) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.After = append(bundle.After, names...)
+	})
+}
+
+// Optional marks bundle as optional. Its boot failure degrades application instead of aborting it.
+func Optional() Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Optional = true
+	})
+}
+
+// EnabledIf enables bundle only if condition is satisfied.
+//
+//	bundle.EnabledIf(slice.InEnv("dev"))
+func EnabledIf(condition slice.Condition) Option {
+	return option(func(bundle *slice.Bundle) {
+		bundle.Condition = condition
+	})
+}
+
 type option func(bundle *slice.Bundle)
 
 func (o option) apply(bundle *slice.Bundle) {
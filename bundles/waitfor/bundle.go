@@ -0,0 +1,202 @@
+// Package waitfor provides a bundle that blocks application start until its
+// dependencies become available.
+package waitfor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/bundle"
+)
+
+// Parameters contains targets that application waits for.
+type Parameters struct {
+	TCP      []string      `envconfig:"wait_tcp" desc:"TCP addresses that must accept connections"`
+	Unix     []string      `envconfig:"wait_unix" desc:"Unix sockets that must exist"`
+	Files    []string      `envconfig:"wait_files" desc:"Files that must exist"`
+	HTTP     []string      `envconfig:"wait_http" desc:"HTTP URLs that must return 2xx status"`
+	Timeout  time.Duration `envconfig:"wait_timeout" default:"4s" desc:"Wait timeout"`
+	Interval time.Duration `envconfig:"wait_interval" default:"500ms" desc:"Check interval"`
+}
+
+// DefaultParameters returns default bundle parameters. Default timeout is less than default
+// application start timeout, so the bundle reports targets that are not ready.
+func DefaultParameters() *Parameters {
+	return &Parameters{
+		Timeout:  4 * time.Second,
+		Interval: 500 * time.Millisecond,
+	}
+}
+
+// Bundle waits for all targets before application start.
+var Bundle = bundle.New(
+	bundle.WithName("waitfor"),
+	bundle.WithParameters(
+		DefaultParameters(),
+	),
+	bundle.WithHooks(
+		slice.Hook{
+			BeforeStart: Wait,
+		},
+	),
+)
+
+// Target is a dependency that application waits for.
+type Target struct {
+	// Kind is a target kind: tcp, unix, file or http.
+	Kind string
+	// Addr is a target address.
+	Addr string
+	// Check checks target availability.
+	Check func(ctx context.Context) error
+}
+
+// String returns target representation.
+func (t Target) String() string {
+	return fmt.Sprintf("%s %s", t.Kind, t.Addr)
+}
+
+// Targets creates targets from parameters.
+func Targets(params *Parameters) (targets []Target) {
+	for _, addr := range params.TCP {
+		targets = append(targets, Target{Kind: "tcp", Addr: addr, Check: dial("tcp", addr)})
+	}
+	for _, addr := range params.Unix {
+		targets = append(targets, Target{Kind: "unix", Addr: addr, Check: socket(addr)})
+	}
+	for _, addr := range params.Files {
+		targets = append(targets, Target{Kind: "file", Addr: addr, Check: stat(addr)})
+	}
+	for _, addr := range params.HTTP {
+		targets = append(targets, Target{Kind: "http", Addr: addr, Check: get(addr)})
+	}
+	return targets
+}
+
+// Wait blocks until all targets are available or timeout exceeded. Timeout, check interval and
+// elapsed time are measured with clock, see slice.WithClock().
+func Wait(ctx context.Context, logger slice.Logger, clock slice.Clock, params *Parameters) error {
+	targets := Targets(params)
+	if len(targets) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	timeout := clock.After(params.Timeout)
+	go func() {
+		select {
+		case <-timeout:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var wg sync.WaitGroup
+	errs := make([]error, len(targets))
+	elapsed := make([]time.Duration, len(targets))
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target Target) {
+			defer wg.Done()
+			elapsed[i], errs[i] = wait(ctx, clock, params.Interval, target)
+		}(i, target)
+	}
+	wg.Wait()
+	// report
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			logger.Printf("waitfor", "%s: not ready in %s: %s", targets[i], elapsed[i], err)
+			failed = append(failed, fmt.Sprintf("%s: %s", targets[i], err))
+			continue
+		}
+		logger.Printf("waitfor", "%s: ready in %s", targets[i], elapsed[i])
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("targets not available: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// wait checks target with interval until success or context done.
+func wait(ctx context.Context, clock slice.Clock, interval time.Duration, target Target) (time.Duration, error) {
+	start := clock.Now()
+	since := func() time.Duration {
+		return clock.Now().Sub(start).Round(time.Millisecond)
+	}
+	var last error
+	for {
+		err := target.Check(ctx)
+		if err == nil {
+			return since(), nil
+		}
+		// check interrupted by timeout, report the previous failure reason
+		if ctx.Err() == nil || last == nil {
+			last = err
+		}
+		select {
+		case <-ctx.Done():
+			return since(), last
+		case <-clock.After(interval):
+		}
+	}
+}
+
+// dial checks that address accepts connections.
+func dial(network, addr string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, network, addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// stat checks that file exists.
+func stat(path string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := os.Stat(path)
+		return err
+	}
+}
+
+// socket checks that unix socket exists.
+func socket(path string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s is not a socket", path)
+		}
+		return nil
+	}
+}
+
+// get checks that url returns 2xx status.
+func get(url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return nil
+	}
+}
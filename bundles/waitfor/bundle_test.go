@@ -0,0 +1,127 @@
+package waitfor_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goava/slice/bundles/waitfor"
+	"github.com/goava/slice/testcmp"
+)
+
+// realClock implements slice.Clock with time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func TestWait(t *testing.T) {
+	t.Run("available targets", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+		dir, err := ioutil.TempDir("", "waitfor")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "ready")
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = ioutil.WriteFile(file, nil, 0600)
+		}()
+		params := waitfor.DefaultParameters()
+		params.TCP = []string{ln.Addr().String()}
+		params.HTTP = []string{srv.URL}
+		params.Files = []string{file}
+		params.Interval = 5 * time.Millisecond
+		logger := &testcmp.Log{}
+		err = waitfor.Wait(context.Background(), logger, realClock{}, params)
+		require.NoError(t, err)
+		require.Len(t, logger.PrintLogs(), 3)
+	})
+
+	t.Run("unavailable targets reported", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+		params := waitfor.DefaultParameters()
+		params.HTTP = []string{srv.URL}
+		params.Unix = []string{"/not/exists.sock"}
+		params.Timeout = 20 * time.Millisecond
+		params.Interval = 5 * time.Millisecond
+		err := waitfor.Wait(context.Background(), &testcmp.Log{}, realClock{}, params)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unix /not/exists.sock: ")
+		require.Contains(t, err.Error(), "http "+srv.URL+": unexpected status 503 Service Unavailable")
+	})
+
+	t.Run("unix socket checked", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "waitfor")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		sock := filepath.Join(dir, "app.sock")
+		ln, err := net.Listen("unix", sock)
+		require.NoError(t, err)
+		defer ln.Close()
+		file := filepath.Join(dir, "app.pid")
+		require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+		params := waitfor.DefaultParameters()
+		params.Unix = []string{sock}
+		require.NoError(t, waitfor.Wait(context.Background(), &testcmp.Log{}, realClock{}, params))
+		params.Unix = []string{file}
+		params.Timeout = 20 * time.Millisecond
+		params.Interval = 5 * time.Millisecond
+		err = waitfor.Wait(context.Background(), &testcmp.Log{}, realClock{}, params)
+		require.EqualError(t, err, "targets not available: unix "+file+": "+file+" is not a socket")
+	})
+
+	t.Run("last failure reported instead of timeout", func(t *testing.T) {
+		var requests int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			// the next check is interrupted by timeout
+			<-r.Context().Done()
+		}))
+		defer srv.Close()
+		params := waitfor.DefaultParameters()
+		params.HTTP = []string{srv.URL}
+		params.Timeout = 50 * time.Millisecond
+		params.Interval = time.Millisecond
+		err := waitfor.Wait(context.Background(), &testcmp.Log{}, realClock{}, params)
+		require.EqualError(t, err, "targets not available: http "+srv.URL+": unexpected status 503 Service Unavailable")
+	})
+
+	t.Run("timeout uses clock", func(t *testing.T) {
+		clock := testcmp.NewClock(time.Now())
+		params := waitfor.DefaultParameters()
+		params.Files = []string{"/not/exists"}
+		params.Timeout = time.Hour
+		params.Interval = 2 * time.Hour
+		logger := &testcmp.Recorder{}
+		done := make(chan error)
+		go func() {
+			done <- waitfor.Wait(context.Background(), logger, clock, params)
+		}()
+		// timeout and interval timers
+		for clock.Waiters() != 2 {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Advance(time.Hour)
+		require.Error(t, <-done)
+		logger.AssertLogged(t, "waitfor", "^file /not/exists: not ready in 1h0m0s: ")
+	})
+}
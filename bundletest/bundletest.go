@@ -0,0 +1,150 @@
+// Package bundletest runs a single bundle with its dependencies in isolation. It is intended for bundle
+// tests:
+//
+//	h := bundletest.New(httpsrv.Bundle,
+//		bundletest.WithStubs(
+//			slice.Supply(http.NewServeMux(), di.As(new(http.Handler))),
+//		),
+//		bundletest.WithParameters(&httpsrv.Parameters{Addr: ":0"}),
+//	)
+//	require.NoError(t, h.Start())
+//	var server *http.Server
+//	require.NoError(t, h.Resolve(&server))
+//	require.NoError(t, h.Shutdown())
+package bundletest
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/goava/di"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/internal/harness"
+)
+
+// Harness runs bundle in isolation.
+type Harness struct {
+	bundle     slice.Bundle
+	stubs      []slice.ComponentOption
+	parameters []slice.Parameter
+	env        map[string]string
+	logger     slice.Logger
+
+	app       *slice.Application
+	container *di.Container
+	done      chan error
+	lock      sync.Mutex
+}
+
+// New creates harness for bundle with provided options.
+func New(bundle slice.Bundle, options ...Option) *Harness {
+	h := &Harness{
+		bundle: bundle,
+		env:    map[string]string{"ENV": "test"},
+		logger: nopLogger{},
+	}
+	for _, opt := range options {
+		opt.apply(h)
+	}
+	return h
+}
+
+// Start starts bundle and its dependencies. It runs BeforeStart hooks and returns when bundle started
+// or its boot failed.
+func (h *Harness) Start() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.app != nil {
+		return errors.New("bundletest: harness already started")
+	}
+	running := make(chan *di.Container, 1)
+	lookup := func(key string) (string, bool) {
+		v, ok := h.env[key]
+		return v, ok
+	}
+	h.app = slice.New(
+		slice.WithName("bundletest"),
+		slice.WithArgs(),
+		slice.WithoutSignals(),
+		slice.WithEnvLookup(lookup),
+		slice.WithLogger(h.logger),
+		slice.WithParameterParser(harness.ParameterParser{Values: h.parameters, Lookup: lookup}),
+		slice.WithBundles(isolate(h.bundle, map[string]slice.Bundle{})),
+		slice.WithComponents(h.stubs...),
+		slice.WithComponents(slice.Provide(harness.Probe(running))),
+	)
+	h.done = make(chan error, 1)
+	go func() {
+		h.done <- h.app.Start()
+	}()
+	select {
+	case h.container = <-running:
+		return nil
+	case err := <-h.done:
+		if err == nil {
+			err = errors.New("bundletest: application stopped before start")
+		}
+		return err
+	}
+}
+
+// Resolve resolves component of bundle or its dependencies. Private components of bundles are not
+// available. Harness must be started.
+func (h *Harness) Resolve(ptr di.Pointer, options ...di.ResolveOption) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.container == nil {
+		return errors.New("bundletest: harness not started")
+	}
+	return h.container.Resolve(ptr, options...)
+}
+
+// Shutdown stops bundle. It runs BeforeShutdown hooks and returns their error.
+func (h *Harness) Shutdown() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.container == nil {
+		return errors.New("bundletest: harness not started")
+	}
+	h.app.Stop()
+	h.container = nil
+	return <-h.done
+}
+
+// isolate copies parameters of bundle and its dependencies, parsing of parameters does not change
+// the original bundle.
+func isolate(bundle slice.Bundle, copies map[string]slice.Bundle) slice.Bundle {
+	if c, ok := copies[bundle.Name]; ok {
+		return c
+	}
+	// cyclic dependencies get the original bundle
+	copies[bundle.Name] = bundle
+	parameters := make([]slice.Parameter, len(bundle.Parameters))
+	for i, p := range bundle.Parameters {
+		parameters[i] = p
+		if rv := reflect.ValueOf(p); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			c := reflect.New(rv.Elem().Type())
+			c.Elem().Set(rv.Elem())
+			parameters[i] = c.Interface()
+		}
+	}
+	bundles := make([]slice.Bundle, len(bundle.Bundles))
+	for i, dep := range bundle.Bundles {
+		bundles[i] = isolate(dep, copies)
+	}
+	bundle.Parameters = parameters
+	bundle.Bundles = bundles
+	copies[bundle.Name] = bundle
+	return bundle
+}
+
+// nopLogger discards messages.
+type nopLogger struct{}
+
+// Printf implements slice.Logger interface.
+func (nopLogger) Printf(bundle string, format string, values ...interface{}) {}
+
+// Fatal implements slice.Logger interface.
+func (nopLogger) Fatal(err error) {}
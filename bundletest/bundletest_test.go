@@ -0,0 +1,122 @@
+package bundletest_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/bundle"
+	"github.com/goava/slice/bundletest"
+)
+
+type Parameters struct {
+	Addr string `envconfig:"addr" default:":8080"`
+}
+
+func TestHarness(t *testing.T) {
+	var calls []string
+	server := bundle.New(
+		bundle.WithName("server"),
+		bundle.WithParameters(&Parameters{}),
+		bundle.WithComponents(
+			slice.Provide(func(p *Parameters, handler http.Handler) *http.Server {
+				return &http.Server{Addr: p.Addr, Handler: handler}
+			}),
+		),
+		bundle.WithHooks(slice.Hook{
+			BeforeStart: func(server *http.Server) {
+				calls = append(calls, "start "+server.Addr)
+			},
+			BeforeShutdown: func(server *http.Server) {
+				calls = append(calls, "shutdown "+server.Addr)
+			},
+		}),
+	)
+
+	t.Run("bundle started with stubs and parameters", func(t *testing.T) {
+		calls = nil
+		mux := http.NewServeMux()
+		h := bundletest.New(server,
+			bundletest.WithStubs(slice.Supply(mux, di.As(new(http.Handler)))),
+			bundletest.WithParameters(&Parameters{Addr: ":9090"}),
+		)
+		require.NoError(t, h.Start())
+		var s *http.Server
+		require.NoError(t, h.Resolve(&s))
+		require.Equal(t, ":9090", s.Addr)
+		require.Equal(t, mux, s.Handler)
+		require.NoError(t, h.Shutdown())
+		require.Equal(t, []string{"start :9090", "shutdown :9090"}, calls)
+	})
+
+	t.Run("default parameters parsed", func(t *testing.T) {
+		calls = nil
+		h := bundletest.New(server,
+			bundletest.WithStubs(slice.Supply(http.NewServeMux(), di.As(new(http.Handler)))),
+		)
+		require.NoError(t, h.Start())
+		require.NoError(t, h.Shutdown())
+		require.Equal(t, []string{"start :8080", "shutdown :8080"}, calls)
+	})
+
+	t.Run("parameters parsed from environment", func(t *testing.T) {
+		calls = nil
+		h := bundletest.New(server,
+			bundletest.WithStubs(slice.Supply(http.NewServeMux(), di.As(new(http.Handler)))),
+			bundletest.WithEnv("ADDR", ":7070"),
+		)
+		require.NoError(t, h.Start())
+		require.NoError(t, h.Shutdown())
+		require.Equal(t, []string{"start :7070", "shutdown :7070"}, calls)
+	})
+
+	t.Run("missing component causes error", func(t *testing.T) {
+		h := bundletest.New(server)
+		err := h.Start()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "http.Handler")
+	})
+
+	t.Run("process environment ignored", func(t *testing.T) {
+		env, ok := os.LookupEnv("ENV")
+		require.NoError(t, os.Setenv("ENV", "prod"))
+		defer func() {
+			if ok {
+				_ = os.Setenv("ENV", env)
+				return
+			}
+			_ = os.Unsetenv("ENV")
+		}()
+		conditional := bundle.New(
+			bundle.WithName("conditional"),
+			bundle.WithComponents(
+				slice.When(slice.IfEnv("MAILER", "fake"), slice.Supply("fake")),
+			),
+		)
+		h := bundletest.New(conditional, bundletest.WithEnv("MAILER", "fake"))
+		require.NoError(t, h.Start())
+		var e slice.Env
+		require.NoError(t, h.Resolve(&e))
+		require.Equal(t, slice.Env("test"), e)
+		var mailer string
+		require.NoError(t, h.Resolve(&mailer))
+		require.Equal(t, "fake", mailer)
+		require.NoError(t, h.Shutdown())
+	})
+
+	t.Run("boot error returned", func(t *testing.T) {
+		failing := bundle.New(
+			bundle.WithName("failing"),
+			bundle.WithHooks(slice.Hook{
+				BeforeStart: func() error { return errors.New("unexpected error") },
+			}),
+		)
+		err := bundletest.New(failing).Start()
+		require.EqualError(t, err, "starting: - boot failing bundle failed: unexpected error\n")
+	})
+}
@@ -0,0 +1,44 @@
+package bundletest
+
+import (
+	"github.com/goava/slice"
+)
+
+// Option configures harness.
+type Option interface {
+	apply(h *Harness)
+}
+
+// WithStubs provides stub components for types that bundle expects from other bundles or application.
+func WithStubs(components ...slice.ComponentOption) Option {
+	return option(func(h *Harness) {
+		h.stubs = append(h.stubs, components...)
+	})
+}
+
+// WithParameters sets parameter values. Parameters must be pointers to structures of bundle
+// parameter types. Parameters that are not set are parsed from environment of harness, see WithEnv().
+func WithParameters(parameters ...slice.Parameter) Option {
+	return option(func(h *Harness) {
+		h.parameters = append(h.parameters, parameters...)
+	})
+}
+
+// WithEnv sets environment variable of application, e.g. for bundle conditions. Harness does not
+// see process environment variables. ENV is "test" by default.
+func WithEnv(key string, value string) Option {
+	return option(func(h *Harness) {
+		h.env[key] = value
+	})
+}
+
+// WithLogger sets logger of harness. By default, messages are discarded.
+func WithLogger(logger slice.Logger) Option {
+	return option(func(h *Harness) {
+		h.logger = logger
+	})
+}
+
+type option func(h *Harness)
+
+func (o option) apply(h *Harness) { o(h) }
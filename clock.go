@@ -0,0 +1,91 @@
+package slice
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides current time and timers. Application provides Clock to the container, so bundles could
+// use it instead of time package and tests could control time, see slice.WithClock().
+type Clock interface {
+	// Now returns current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock uses time package.
+type realClock struct{}
+
+// Now implements Clock interface.
+func (realClock) Now() time.Time { return time.Now() }
+
+// After implements Clock interface.
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// contextWithTimeout returns context that is done after timeout of clock.
+func contextWithTimeout(parent context.Context, clock Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeout(parent, timeout)
+	}
+	ctx := &clockContext{
+		parent: parent,
+		done:   make(chan struct{}),
+	}
+	after := clock.After(timeout)
+	go func() {
+		select {
+		case <-after:
+			ctx.cancel(context.DeadlineExceeded)
+		case <-parent.Done():
+			ctx.cancel(parent.Err())
+		case <-ctx.done:
+		}
+	}()
+	return ctx, func() { ctx.cancel(context.Canceled) }
+}
+
+// clockContext is a context that is done after timeout of Clock. It does not share cancellation with
+// parent internals, so child contexts get its error, e.g. context.DeadlineExceeded. Time of Clock may
+// differ from wall-clock time, so it is not reported as deadline.
+type clockContext struct {
+	parent context.Context
+	done   chan struct{}
+	once   sync.Once
+	lock   sync.Mutex
+	err    error
+}
+
+// cancel closes done channel with error. Only the first call has effect.
+func (c *clockContext) cancel(err error) {
+	c.once.Do(func() {
+		c.lock.Lock()
+		c.err = err
+		c.lock.Unlock()
+		close(c.done)
+	})
+}
+
+// Deadline implements context.Context interface. Only deadline of parent is returned, network calls
+// and other standard library functions treat deadline as wall-clock time.
+func (c *clockContext) Deadline() (time.Time, bool) {
+	return c.parent.Deadline()
+}
+
+// Done implements context.Context interface.
+func (c *clockContext) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err implements context.Context interface.
+func (c *clockContext) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.err
+}
+
+// Value implements context.Context interface.
+func (c *clockContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
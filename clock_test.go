@@ -0,0 +1,144 @@
+package slice
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/goava/di"
+	"github.com/goava/slice/testcmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock(t *testing.T) {
+	// advance waits for the number of pending timers and advances clock
+	advance := func(clock *testcmp.Clock, waiters int, d time.Duration) {
+		for clock.Waiters() != waiters {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Advance(d)
+	}
+
+	t.Run("retry backoff uses clock", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		clock := testcmp.NewClock(time.Now())
+		attempts := 0
+		bundle := Bundle{
+			Name: "retry-bundle",
+			Hooks: []Hook{{
+				BeforeStart: func() error {
+					attempts++
+					if attempts < 3 {
+						return errors.New("not ready")
+					}
+					return nil
+				},
+				Retry: &RetryPolicy{Backoff: time.Hour},
+			}},
+		}
+		done := make(chan error)
+		go func() {
+			_, err := beforeStart(context.Background(), c, &testcmp.Recorder{}, clock, observer{}, &Health{}, bundle)
+			done <- err
+		}()
+		advance(clock, 1, time.Hour)
+		advance(clock, 1, 2*time.Hour)
+		require.NoError(t, <-done)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("timeout uses clock", func(t *testing.T) {
+		clock := testcmp.NewClock(time.Now())
+		ctx, cancel := contextWithTimeout(context.Background(), clock, time.Second)
+		defer cancel()
+		clock.Advance(time.Second - time.Nanosecond)
+		require.NoError(t, ctx.Err())
+		clock.Advance(time.Nanosecond)
+		<-ctx.Done()
+		require.Equal(t, context.DeadlineExceeded, ctx.Err())
+	})
+
+	t.Run("timeout context does not report deadline of clock", func(t *testing.T) {
+		clock := testcmp.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		ctx, cancel := contextWithTimeout(context.Background(), clock, time.Minute)
+		defer cancel()
+		_, ok := ctx.Deadline()
+		require.False(t, ok)
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Hour)
+		defer cancelParent()
+		ctx, cancel = contextWithTimeout(parent, clock, time.Minute)
+		defer cancel()
+		want, _ := parent.Deadline()
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		require.Equal(t, want, deadline)
+	})
+
+	t.Run("start context of past clock dials network", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		dial := func(ctx context.Context) error {
+			conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", listener.Addr().String())
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+		dispatcher := &DispatcherMock{RunFunc: func(ctx context.Context) error { return nil }}
+		app := New(
+			WithName("app"),
+			WithArgs(),
+			WithEnvLookup(func(key string) (string, bool) { return "", false }),
+			WithLogger(&testcmp.Log{}),
+			WithClock(testcmp.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))),
+			WithBundles(Bundle{Name: "dialer", Hooks: []Hook{{BeforeStart: dial}}}),
+			WithComponents(Supply(dispatcher, di.As(new(Dispatcher)))),
+		)
+		app.StartTimeout = time.Minute
+		require.NoError(t, app.Start())
+	})
+
+	t.Run("child context gets deadline exceeded", func(t *testing.T) {
+		clock := testcmp.NewClock(time.Now())
+		ctx, cancel := contextWithTimeout(context.Background(), clock, time.Second)
+		defer cancel()
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+		advance(clock, 1, time.Second)
+		<-child.Done()
+		require.Equal(t, context.DeadlineExceeded, child.Err())
+	})
+
+	t.Run("timeout context canceled", func(t *testing.T) {
+		clock := testcmp.NewClock(time.Now())
+		ctx, cancel := contextWithTimeout(context.Background(), clock, time.Second)
+		cancel()
+		<-ctx.Done()
+		require.Equal(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("clock provided to container", func(t *testing.T) {
+		clock := testcmp.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		var now time.Time
+		dispatcher := func(clock Clock) Dispatcher {
+			return &DispatcherMock{RunFunc: func(ctx context.Context) error {
+				now = clock.Now()
+				return nil
+			}}
+		}
+		app := New(
+			WithName("app"),
+			WithArgs(),
+			WithEnvLookup(func(key string) (string, bool) { return "", false }),
+			WithLogger(&testcmp.Recorder{}),
+			WithClock(clock),
+			WithComponents(Provide(dispatcher)),
+		)
+		require.NoError(t, app.Start())
+		require.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), now)
+	})
+}
@@ -1,6 +1,10 @@
 package slice
 
-import "github.com/goava/di"
+import (
+	"reflect"
+
+	"github.com/goava/di"
+)
 
 // ComponentOption modifies application components.
 type ComponentOption interface {
@@ -12,14 +16,19 @@ type ComponentOption interface {
 // add additional behavior to the process of type resolving.
 func Provide(constructor di.Constructor, options ...di.ProvideOption) ComponentOption {
 	return option(func(s *Application) {
-		s.providers = append(s.providers, di.Provide(constructor, options...))
+		c := newComponent(s.bundle, constructor, nil, options)
+		c.private = s.private
+		s.components = append(s.components, c)
 	})
 }
 
 // Supply provides value as is.
 func Supply(value di.Value, options ...di.ProvideOption) ComponentOption {
 	return option(func(s *Application) {
-		s.providers = append(s.providers, di.ProvideValue(value, options...))
+		c := newComponent(s.bundle, nil, value, options)
+		c.supplied = true
+		c.private = s.private
+		s.components = append(s.components, c)
 	})
 }
 
@@ -31,3 +40,230 @@ func Group(options ...ComponentOption) ComponentOption {
 		}
 	})
 }
+
+// component is a registered application component. Components are kept as is until container
+// creation. It allows to inspect them before.
+type component struct {
+	// bundle is a name of bundle that registered component, empty for application components
+	bundle string
+	// constructor or value of component
+	constructor di.Constructor
+	value       di.Value
+	supplied    bool
+	// private component visible only to its bundle, see slice.Private()
+	private bool
+	// options contains provide options as is
+	options []di.ProvideOption
+}
+
+// newComponent creates component.
+func newComponent(bundle string, constructor di.Constructor, value di.Value, options []di.ProvideOption) component {
+	return component{
+		bundle:      bundle,
+		constructor: constructor,
+		value:       value,
+		options:     options,
+	}
+}
+
+// option returns container option of component.
+func (c component) option() di.Option {
+	if c.supplied {
+		return di.ProvideValue(c.value, c.options...)
+	}
+	return di.Provide(c.constructor, c.options...)
+}
+
+// result returns type of constructor result or supplied value.
+func (c component) result() reflect.Type {
+	if c.supplied {
+		return reflect.TypeOf(c.value)
+	}
+	rt := reflect.TypeOf(c.constructor)
+	if rt == nil || rt.Kind() != reflect.Func || rt.NumOut() == 0 {
+		return nil
+	}
+	return rt.Out(0)
+}
+
+// provides checks that component provides type t. Provide options are opaque, so interfaces are
+// checked with a probe container.
+func (c component) provides(t reflect.Type) bool {
+	return c.providesWith(t, c.options...)
+}
+
+// visibleProviders returns indexes of components that provide type t to bundle. Private components are
+// visible to their own bundle only and take precedence over exported ones, as in bundle container.
+func visibleProviders(components []component, t reflect.Type, bundle string) []int {
+	var exported, private []int
+	for i, c := range components {
+		if !c.provides(t) {
+			continue
+		}
+		switch {
+		case !c.private:
+			exported = append(exported, i)
+		case bundle != "" && c.bundle == bundle:
+			private = append(private, i)
+		}
+	}
+	if len(private) > 0 {
+		return private
+	}
+	return exported
+}
+
+// providesWith checks that component with provide options provides type t.
+func (c component) providesWith(t reflect.Type, options ...di.ProvideOption) bool {
+	rt := c.result()
+	if rt == nil || t == nil {
+		return false
+	}
+	if rt == t {
+		return true
+	}
+	if t.Kind() != reflect.Interface || !rt.Implements(t) {
+		return false
+	}
+	container, err := probe(rt, options)
+	if err != nil {
+		// invalid component will be reported on container creation
+		return false
+	}
+	has, err := container.Has(reflect.New(t).Interface())
+	return err == nil && has
+}
+
+// types returns the result type of component and interfaces from candidates that component provides.
+func (c component) types(candidates []reflect.Type) (types []reflect.Type) {
+	rt := c.result()
+	if rt == nil {
+		return nil
+	}
+	types = append(types, rt)
+	seen := map[reflect.Type]bool{rt: true}
+	for _, t := range candidates {
+		if !seen[t] && c.provides(t) {
+			types = append(types, t)
+		}
+		seen[t] = true
+	}
+	return types
+}
+
+// tags returns tags of component.
+func (c component) tags() (tags di.Tags) {
+	rt := c.result()
+	if rt == nil {
+		return nil
+	}
+	container, err := probe(rt, c.options)
+	if err != nil {
+		return nil
+	}
+	group := reflect.New(reflect.SliceOf(rt)).Interface()
+	_ = container.Iterate(group, func(t di.Tags, _ di.ValueFunc) error {
+		tags = t
+		return nil
+	})
+	return tags
+}
+
+// probe creates container with constructor of type rt and provide options. Constructor dependencies
+// do not matter for provide options, so the constructor has no parameters.
+func probe(rt reflect.Type, options []di.ProvideOption) (*di.Container, error) {
+	fn := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{rt}, false), func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.Zero(rt)}
+	})
+	return di.New(di.Provide(fn.Interface(), options...))
+}
+
+// withoutInterface returns provide options of component without options that bind it to
+// interface t. Options are checked one by one, so all interfaces of such option are removed.
+func (c component) withoutInterface(t reflect.Type) (options []di.ProvideOption) {
+	for _, opt := range c.options {
+		if params, ok := opt.(di.ProvideParams); ok {
+			var interfaces []di.Interface
+			for _, i := range params.Interfaces {
+				if reflect.TypeOf(i).Elem() != t {
+					interfaces = append(interfaces, i)
+				}
+			}
+			params.Interfaces = interfaces
+			options = append(options, params)
+			continue
+		}
+		if c.providesWith(t, opt) {
+			continue
+		}
+		options = append(options, opt)
+	}
+	return options
+}
+
+// dependencies returns types that component depends on.
+func (c component) dependencies() []reflect.Type {
+	return invocationDependencies(c.constructor)
+}
+
+var injectType = reflect.TypeOf(di.Inject{})
+
+// invocationDependencies returns parameter types of function. Groups are replaced by their element type,
+// structures with di.Inject are replaced by types of their fields.
+func invocationDependencies(fn interface{}) (types []reflect.Type) {
+	rt := reflect.TypeOf(fn)
+	if rt == nil || rt.Kind() != reflect.Func {
+		return nil
+	}
+	for i := 0; i < rt.NumIn(); i++ {
+		types = append(types, dependencyTypes(rt.In(i))...)
+	}
+	return types
+}
+
+// dependencyTypes returns types that parameter of type t depends on.
+func dependencyTypes(t reflect.Type) (types []reflect.Type) {
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if st.Kind() == reflect.Struct {
+		if f, ok := st.FieldByName("Inject"); ok && f.Anonymous && f.Type == injectType {
+			for i := 0; i < st.NumField(); i++ {
+				if field := st.Field(i); !field.Anonymous && field.PkgPath == "" {
+					types = append(types, dependencyTypes(field.Type)...)
+				}
+			}
+			return types
+		}
+	}
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return []reflect.Type{t}
+}
+
+// consumedTypes returns types that components, hooks and checks depend on and types declared
+// by bundle contracts. Provide options are opaque, so these types are candidates for types().
+func consumedTypes(bundles []Bundle, components []component) (types []reflect.Type) {
+	for _, c := range components {
+		types = append(types, c.dependencies()...)
+	}
+	for _, b := range bundles {
+		for _, h := range b.Hooks {
+			types = append(types, invocationDependencies(h.BeforeStart)...)
+			types = append(types, invocationDependencies(h.BeforeShutdown)...)
+		}
+		for _, c := range b.Checks {
+			types = append(types, invocationDependencies(c.Run)...)
+		}
+		for _, ptrs := range [][]di.Pointer{b.Requires, b.Provides} {
+			for _, ptr := range ptrs {
+				if rt := reflect.TypeOf(ptr); rt != nil && rt.Kind() == reflect.Ptr {
+					types = append(types, rt.Elem())
+				}
+			}
+		}
+	}
+	return types
+}
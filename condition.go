@@ -0,0 +1,130 @@
+package slice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Condition reports whether conditional options should be applied. Conditions are evaluated
+// on application start, after environment parsing.
+type Condition func(info Info) bool
+
+// When applies options only if condition is true.
+//
+//	slice.WithComponents(
+//		slice.When(slice.InEnv("dev"),
+//			slice.Provide(NewFakeMailer, di.As(new(Mailer))),
+//		),
+//		slice.When(slice.Not(slice.InEnv("dev")),
+//			slice.Provide(NewSMTPMailer, di.As(new(Mailer))),
+//		),
+//	)
+//
+// Bundles registered with slice.WithBundles() inside When must be conditional on application level.
+// Use bundle.EnabledIf() to make bundle dependency conditional.
+func When(condition Condition, options ...ComponentOption) ComponentOption {
+	return option(func(s *Application) {
+		c := conditional{
+			bundle:    s.bundle,
+			condition: condition,
+			options:   options,
+		}
+		// options applied on start are evaluated immediately
+		if s.info != nil {
+			s.applyConditional(c)
+			return
+		}
+		s.conditionals = append(s.conditionals, c)
+	})
+}
+
+// InEnv checks that application environment has one of the prefixes. See Env.IsDev().
+func InEnv(envs ...string) Condition {
+	return func(info Info) bool {
+		for _, env := range envs {
+			if strings.HasPrefix(strings.ToLower(info.Env.String()), strings.ToLower(env)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IfEnv checks that environment variable key has value. The variable is looked up as is, application
+// prefix is not added. See WithEnvLookup().
+func IfEnv(key string, value string) Condition {
+	return func(info Info) bool {
+		v, ok := info.lookupEnv(key)
+		return ok && v == value
+	}
+}
+
+// IfParameter checks parameter with predicate. Predicate is a function that takes pointer to parameter
+// structure and reports whether condition is satisfied:
+//
+//	slice.When(slice.IfParameter(func(p *MailParameters) bool { return p.Fake }),
+//		slice.Provide(NewFakeMailer, di.As(new(Mailer))),
+//	)
+//
+// The parameter is parsed with application prefix and parameter parser of slice.WithParameterParser().
+// Parser provided as component is not available on condition evaluation, the default parser is used
+// instead. Parse error fails application start.
+func IfParameter(predicate interface{}) Condition {
+	fn := reflect.ValueOf(predicate)
+	ft := reflect.TypeOf(predicate)
+	if ft == nil || ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.In(0).Kind() != reflect.Ptr ||
+		ft.NumOut() != 1 || ft.Out(0).Kind() != reflect.Bool {
+		panic(fmt.Sprintf("slice: IfParameter predicate must be func(*Parameters) bool, got %T", predicate))
+	}
+	return func(info Info) bool {
+		parameter := reflect.New(ft.In(0).Elem())
+		if err := info.parseParameter(parameter.Interface()); err != nil {
+			return false
+		}
+		return fn.Call([]reflect.Value{parameter})[0].Bool()
+	}
+}
+
+// IfDebug checks that application runs in debug mode.
+func IfDebug() Condition {
+	return func(info Info) bool {
+		return info.Debug
+	}
+}
+
+// Not inverts condition.
+func Not(condition Condition) Condition {
+	return func(info Info) bool {
+		return !condition(info)
+	}
+}
+
+type conditional struct {
+	bundle    string
+	condition Condition
+	options   []ComponentOption
+}
+
+// applyConditionals applies options of satisfied conditions registered before start. Conditions
+// registered after this call, e.g. in bundles and nested conditions, are evaluated immediately.
+func (app *Application) applyConditionals(info Info) {
+	app.info = &info
+	for _, c := range app.conditionals {
+		app.applyConditional(c)
+	}
+	app.conditionals = nil
+}
+
+// applyConditional applies options of conditional if its condition is satisfied.
+func (app *Application) applyConditional(c conditional) {
+	if !c.condition(*app.info) {
+		return
+	}
+	bundle := app.bundle
+	app.bundle = c.bundle
+	for _, opt := range c.options {
+		opt.apply(app)
+	}
+	app.bundle = bundle
+}
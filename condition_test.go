@@ -0,0 +1,160 @@
+package slice_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/bundle"
+	"github.com/goava/slice/testcmp"
+)
+
+type Mailer interface {
+	Name() string
+}
+
+type mailer string
+
+func (m mailer) Name() string { return string(m) }
+
+func TestWhen(t *testing.T) {
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+	}()
+	os.Args = []string{"app"}
+	_ = os.Setenv("DEBUG", "")
+	defer os.Setenv("ENV", "")
+
+	run := func(env string, options ...slice.Option) (names []string) {
+		_ = os.Setenv("ENV", env)
+		dispatcher := func(mailers []Mailer) *testcmp.FuncDispatcher {
+			return &testcmp.FuncDispatcher{RunFunc: func(ctx context.Context) error {
+				for _, m := range mailers {
+					names = append(names, m.Name())
+				}
+				return nil
+			}}
+		}
+		slice.Run(append(options,
+			slice.WithName("app"),
+			slice.WithComponents(
+				slice.Provide(dispatcher, di.As(new(slice.Dispatcher))),
+			),
+		)...)
+		return names
+	}
+
+	components := slice.WithComponents(
+		slice.When(slice.InEnv("dev"),
+			slice.Supply(mailer("fake"), di.As(new(Mailer))),
+		),
+		slice.When(slice.Not(slice.InEnv("dev")),
+			slice.Supply(mailer("smtp"), di.As(new(Mailer))),
+		),
+	)
+
+	t.Run("component provided in matching environment", func(t *testing.T) {
+		require.Equal(t, []string{"fake"}, run("dev-1", components))
+		require.Equal(t, []string{"smtp"}, run("prod", components))
+	})
+
+	t.Run("nested conditions in bundle", func(t *testing.T) {
+		b := bundle.New(
+			bundle.WithName("mail"),
+			bundle.WithComponents(
+				slice.When(slice.InEnv("dev", "test"),
+					slice.When(slice.IfEnv("MAILER_FAKE", "true"),
+						slice.Supply(mailer("fake"), di.As(new(Mailer))),
+					),
+				),
+				slice.Supply(mailer("smtp"), di.As(new(Mailer))),
+			),
+		)
+		_ = os.Setenv("MAILER_FAKE", "true")
+		defer os.Unsetenv("MAILER_FAKE")
+		require.Equal(t, []string{"fake", "smtp"}, run("test", slice.WithBundles(b)))
+		require.Equal(t, []string{"smtp"}, run("prod", slice.WithBundles(b)))
+	})
+
+	t.Run("disabled bundle skipped", func(t *testing.T) {
+		b := bundle.New(
+			bundle.WithName("fake-mail"),
+			bundle.EnabledIf(slice.InEnv("dev")),
+			bundle.WithComponents(
+				slice.Supply(mailer("fake"), di.As(new(Mailer))),
+			),
+		)
+		require.Equal(t, []string{"fake"}, run("dev", slice.WithBundles(b)))
+		smtp := slice.WithComponents(slice.Supply(mailer("smtp"), di.As(new(Mailer))))
+		require.Equal(t, []string{"smtp"}, run("prod", slice.WithBundles(b), smtp))
+	})
+}
+
+type MailParameters struct {
+	Fake bool `envconfig:"mailer_fake"`
+}
+
+func TestIfParameter(t *testing.T) {
+	start := func(prefix string, env map[string]string) (names []string, err error) {
+		dispatcher := func(mailers []Mailer) *testcmp.FuncDispatcher {
+			return &testcmp.FuncDispatcher{RunFunc: func(ctx context.Context) error {
+				for _, m := range mailers {
+					names = append(names, m.Name())
+				}
+				return nil
+			}}
+		}
+		app := slice.New(
+			slice.WithName("app"),
+			slice.WithArgs(),
+			slice.WithLogger(&testcmp.Log{}),
+			slice.WithEnvLookup(func(key string) (string, bool) {
+				v, ok := env[key]
+				return v, ok
+			}),
+			slice.WithComponents(
+				slice.When(slice.IfParameter(func(p *MailParameters) bool { return p.Fake }),
+					slice.Supply(mailer("fake"), di.As(new(Mailer))),
+				),
+				slice.When(slice.Not(slice.IfParameter(func(p *MailParameters) bool { return p.Fake })),
+					slice.Supply(mailer("smtp"), di.As(new(Mailer))),
+				),
+				slice.Provide(dispatcher, di.As(new(slice.Dispatcher))),
+			),
+		)
+		app.Prefix = prefix
+		return names, app.Start()
+	}
+
+	t.Run("component provided by parsed parameter", func(t *testing.T) {
+		names, err := start("", map[string]string{"MAILER_FAKE": "true"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"fake"}, names)
+		names, err = start("", nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"smtp"}, names)
+	})
+
+	t.Run("parameter parsed with application prefix", func(t *testing.T) {
+		names, err := start("mail", map[string]string{"MAILER_FAKE": "true", "MAIL_MAILER_FAKE": "false"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"smtp"}, names)
+	})
+
+	t.Run("parse error fails start", func(t *testing.T) {
+		_, err := start("", map[string]string{"MAILER_FAKE": "maybe"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "conditions: parameters: ")
+	})
+
+	t.Run("invalid predicate", func(t *testing.T) {
+		require.PanicsWithValue(t, "slice: IfParameter predicate must be func(*Parameters) bool, got func(slice_test.MailParameters) bool", func() {
+			slice.IfParameter(func(p MailParameters) bool { return p.Fake })
+		})
+	})
+}
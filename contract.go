@@ -0,0 +1,74 @@
+package slice
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/goava/di"
+)
+
+// checkContracts checks that bundle requirements are met and bundles provide declared types.
+// All violations returned at once.
+func checkContracts(container *di.Container, bundles []Bundle, components []component) error {
+	provides := func(bundle string, t reflect.Type) bool {
+		for _, c := range components {
+			if c.bundle == bundle && !c.private && c.provides(t) {
+				return true
+			}
+		}
+		return false
+	}
+	var errs contractErrors
+	for _, b := range bundles {
+		for _, ptr := range b.Requires {
+			rt := reflect.TypeOf(ptr)
+			if rt == nil || rt.Kind() != reflect.Ptr {
+				errs = append(errs, fmt.Errorf("%s requires %v: not a pointer", b.Name, rt))
+				continue
+			}
+			has, err := container.Has(ptr)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s requires %s: %w", b.Name, rt.Elem(), err))
+				continue
+			}
+			if !has {
+				errs = append(errs, fmt.Errorf("%s requires %s: not provided", b.Name, rt.Elem()))
+			}
+		}
+		for _, ptr := range b.Provides {
+			rt := reflect.TypeOf(ptr)
+			if rt == nil || rt.Kind() != reflect.Ptr {
+				errs = append(errs, fmt.Errorf("%s provides %v: not a pointer", b.Name, rt))
+				continue
+			}
+			if !provides(b.Name, rt.Elem()) && !providesParameter(b, rt.Elem()) {
+				errs = append(errs, fmt.Errorf("%s provides %s: not provided by bundle components", b.Name, rt.Elem()))
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// providesParameter checks that bundle has parameter of type t.
+func providesParameter(b Bundle, t reflect.Type) bool {
+	for _, p := range b.Parameters {
+		if reflect.TypeOf(p) == t {
+			return true
+		}
+	}
+	return false
+}
+
+type contractErrors []error
+
+// Error implements error interface.
+func (e contractErrors) Error() (r string) {
+	r = "bundle contracts violated:\n"
+	for _, err := range e {
+		r = fmt.Sprintf("%s- %s\n", r, err)
+	}
+	return r
+}
@@ -0,0 +1,56 @@
+package slice
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckContracts(t *testing.T) {
+	app := &Application{}
+	server := Bundle{
+		Name: "http",
+		Components: []ComponentOption{
+			Provide(func(handler http.Handler) *http.Server { return &http.Server{Handler: handler} }),
+		},
+		Requires: []di.Pointer{new(http.Handler)},
+		Provides: []di.Pointer{new(*http.Server)},
+	}
+	mux := Bundle{
+		Name: "mux",
+		Components: []ComponentOption{
+			Provide(http.NewServeMux, di.As(new(http.Handler))),
+		},
+		Provides: []di.Pointer{new(http.Handler)},
+	}
+	server.apply(app)
+	mux.apply(app)
+	var options []di.Option
+	for _, c := range app.components {
+		options = append(options, c.option())
+	}
+
+	t.Run("contracts satisfied", func(t *testing.T) {
+		c, err := di.New(options...)
+		require.NoError(t, err)
+		require.NoError(t, checkContracts(c, []Bundle{mux, server}, app.components))
+	})
+
+	t.Run("all violations reported", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		broken := Bundle{
+			Name:     "broken",
+			Requires: []di.Pointer{new(*http.Client)},
+			Provides: []di.Pointer{new(*http.ServeMux), "string"},
+		}
+		err = checkContracts(c, []Bundle{server, broken}, app.components)
+		require.EqualError(t, err, "bundle contracts violated:\n"+
+			"- http requires http.Handler: not provided\n"+
+			"- broken requires *http.Client: not provided\n"+
+			"- broken provides *http.ServeMux: not provided by bundle components\n"+
+			"- broken provides string: not a pointer\n")
+	})
+}
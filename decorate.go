@@ -0,0 +1,168 @@
+package slice
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Decorate registers decorator of component provided by application or another bundle. Decorator is a
+// function that takes component and its own dependencies and returns decorated component of the same type:
+//
+//	func TraceHandler(handler http.Handler, tracer *Tracer) http.Handler {
+//		return tracer.Middleware(handler)
+//	}
+//
+// Decorator may return error as the second result. Decorators are applied after the original constructor
+// in bundle order, application decorators are applied last. Logger could be decorated as well, parameters
+// could not.
+func Decorate(fn interface{}) ComponentOption {
+	return option(func(s *Application) {
+		s.decorators = append(s.decorators, decorator{
+			bundle: s.bundle,
+			fn:     fn,
+		})
+	})
+}
+
+type decorator struct {
+	bundle string
+	fn     interface{}
+}
+
+// decorateLogger appends component that provides logger if logger is decorated and not provided by
+// components. The decorated logger replaces application logger on configuring.
+func decorateLogger(components []component, decorators []decorator, logger Logger) []component {
+	for _, c := range components {
+		if c.provides(loggerType) {
+			return components
+		}
+	}
+	for _, d := range decorators {
+		ft := reflect.TypeOf(d.fn)
+		if ft != nil && ft.Kind() == reflect.Func && ft.NumIn() > 0 && ft.In(0) == loggerType {
+			return append(components, newComponent("", func() Logger { return logger }, nil, nil))
+		}
+	}
+	return components
+}
+
+// decorate applies decorators to components in bundle order. Parameters are application parameters.
+func decorate(components []component, decorators []decorator, bundles []Bundle, parameters []Parameter) ([]component, error) {
+	order := map[string]int{}
+	for i, b := range bundles {
+		order[b.Name] = i
+	}
+	index := func(d decorator) int {
+		if d.bundle == "" {
+			return len(bundles)
+		}
+		return order[d.bundle]
+	}
+	sorted := append([]decorator(nil), decorators...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return index(sorted[i]) < index(sorted[j])
+	})
+	components = append([]component(nil), components...)
+	for _, d := range sorted {
+		fn := reflect.ValueOf(d.fn)
+		ft := fn.Type()
+		if ft.Kind() != reflect.Func || ft.NumIn() == 0 || ft.NumOut() == 0 || ft.NumOut() > 2 ||
+			ft.Out(0) != ft.In(0) || ft.NumOut() == 2 && ft.Out(1) != errorType {
+			return nil, fmt.Errorf("decorate: invalid decorator signature, got %s", ft)
+		}
+		rt := ft.In(0)
+		found := visibleProviders(components, rt, d.bundle)
+		if len(found) == 0 && isParameter(rt, bundles, parameters) {
+			return nil, fmt.Errorf("decorate %s: parameters could not be decorated", rt)
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("decorate %s: type not provided", rt)
+		}
+		if len(found) > 1 {
+			return nil, fmt.Errorf("decorate %s: multiple definitions", rt)
+		}
+		c := components[found[0]]
+		result := c.result()
+		if result == rt {
+			components[found[0]] = decorated(c, fn)
+			continue
+		}
+		// type provided as interface: the decorated interface will be provided by separate component
+		c.options = c.withoutInterface(rt)
+		components[found[0]] = c
+		identity := reflect.MakeFunc(reflect.FuncOf([]reflect.Type{result}, []reflect.Type{result}, false), func(args []reflect.Value) []reflect.Value {
+			return args
+		})
+		components = append(components, decorated(component{
+			bundle:      c.bundle,
+			constructor: identity.Interface(),
+			private:     c.private,
+		}, fn))
+	}
+	return components, nil
+}
+
+// isParameter checks that type is a type of application or bundle parameter.
+func isParameter(t reflect.Type, bundles []Bundle, parameters []Parameter) bool {
+	all := append([]Parameter(nil), parameters...)
+	for _, b := range bundles {
+		all = append(all, b.Parameters...)
+	}
+	for _, p := range all {
+		if reflect.TypeOf(p) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// decorated returns component whose constructor calls the original constructor and then decorator.
+func decorated(c component, fn reflect.Value) component {
+	ft := fn.Type()
+	orig := reflect.ValueOf(c.constructor)
+	if c.supplied {
+		value := reflect.ValueOf(c.value)
+		orig = reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{value.Type()}, false), func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{value}
+		})
+	}
+	ot := orig.Type()
+	var in, out []reflect.Type
+	for i := 0; i < ot.NumIn(); i++ {
+		in = append(in, ot.In(i))
+	}
+	for i := 1; i < ft.NumIn(); i++ {
+		in = append(in, ft.In(i))
+	}
+	out = append(out, ft.Out(0))
+	for i := 1; i < ot.NumOut(); i++ {
+		out = append(out, ot.Out(i))
+	}
+	hasError := out[len(out)-1] == errorType
+	if !hasError {
+		out = append(out, errorType)
+	}
+	constructor := reflect.MakeFunc(reflect.FuncOf(in, out, false), func(args []reflect.Value) []reflect.Value {
+		results := orig.Call(args[:ot.NumIn()])
+		if !hasError {
+			results = append(results, reflect.Zero(errorType))
+		}
+		if !results[len(results)-1].IsNil() {
+			results[0] = reflect.Zero(ft.Out(0))
+			return results
+		}
+		decorated := fn.Call(append([]reflect.Value{results[0]}, args[ot.NumIn():]...))
+		results[0] = decorated[0]
+		if len(decorated) == 2 {
+			results[len(results)-1] = decorated[1]
+		}
+		return results
+	})
+	c.constructor = constructor.Interface()
+	c.supplied = false
+	c.value = nil
+	return c
+}
+
+var loggerType = reflect.TypeOf(new(Logger)).Elem()
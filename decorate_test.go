@@ -0,0 +1,199 @@
+package slice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/goava/slice/testcmp"
+	"github.com/stretchr/testify/require"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type greeting string
+
+func (g greeting) Greet() string { return string(g) }
+
+func TestDecorate(t *testing.T) {
+	t.Run("decorators applied in bundle order", func(t *testing.T) {
+		provider := Bundle{
+			Name: "provider",
+			Components: []ComponentOption{
+				Provide(func() greeting { return "hello" }),
+				Supply(", "),
+			},
+		}
+		first := Bundle{
+			Name: "first",
+			Components: []ComponentOption{
+				Decorate(func(g greeting, sep string) greeting { return g + greeting(sep) + "first" }),
+			},
+		}
+		second := Bundle{
+			Name: "second",
+			Components: []ComponentOption{
+				Decorate(func(g greeting) (greeting, error) { return g + " second", nil }),
+			},
+		}
+		c, err := buildContainer([]Bundle{provider, second, first})
+		require.NoError(t, err)
+		var g greeting
+		require.NoError(t, c.Resolve(&g))
+		require.Equal(t, greeting("hello second, first"), g)
+	})
+
+	t.Run("interface provided with di.As decorated", func(t *testing.T) {
+		provider := Bundle{
+			Name: "provider",
+			Components: []ComponentOption{
+				Supply(greeting("hello"), di.As(new(greeter))),
+			},
+		}
+		decorator := Bundle{
+			Name: "decorator",
+			Components: []ComponentOption{
+				Decorate(func(g greeter) greeter { return greeting(g.Greet() + " world") }),
+			},
+		}
+		c, err := buildContainer([]Bundle{provider, decorator})
+		require.NoError(t, err)
+		var g greeter
+		require.NoError(t, c.Resolve(&g))
+		require.Equal(t, "hello world", g.Greet())
+		var original greeting
+		require.NoError(t, c.Resolve(&original))
+		require.Equal(t, greeting("hello"), original)
+	})
+
+	t.Run("decorator error returned on resolve", func(t *testing.T) {
+		b := Bundle{
+			Name: "bundle",
+			Components: []ComponentOption{
+				Provide(http.NewServeMux),
+				Decorate(func(mux *http.ServeMux) (*http.ServeMux, error) { return nil, errors.New("decorate failed") }),
+			},
+		}
+		c, err := buildContainer([]Bundle{b})
+		require.NoError(t, err)
+		var mux *http.ServeMux
+		require.Error(t, c.Resolve(&mux))
+	})
+
+	t.Run("not provided type cause error", func(t *testing.T) {
+		b := Bundle{
+			Name: "bundle",
+			Components: []ComponentOption{
+				Decorate(func(h http.Handler) http.Handler { return h }),
+			},
+		}
+		_, err := buildContainer([]Bundle{b})
+		require.EqualError(t, err, "decorate http.Handler: type not provided")
+	})
+
+	t.Run("private components of other bundles ignored", func(t *testing.T) {
+		pools := Bundle{
+			Name: "pools",
+			Components: []ComponentOption{
+				Private(Provide(func() greeting { return "private" })),
+			},
+		}
+		provider := Bundle{
+			Name: "provider",
+			Components: []ComponentOption{
+				Provide(func() greeting { return "exported" }),
+			},
+		}
+		decorator := Bundle{
+			Name: "decorator",
+			Components: []ComponentOption{
+				Decorate(func(g greeting) greeting { return g + "!" }),
+			},
+		}
+		container, _, err := buildScopes([]Bundle{pools, provider, decorator})
+		require.NoError(t, err)
+		var g greeting
+		require.NoError(t, container.Resolve(&g))
+		require.Equal(t, greeting("exported!"), g)
+		_, _, err = buildScopes([]Bundle{pools, decorator})
+		require.EqualError(t, err, "decorate slice.greeting: type not provided")
+	})
+
+	t.Run("private component decorated by its bundle", func(t *testing.T) {
+		pools := Bundle{
+			Name: "pools",
+			Components: []ComponentOption{
+				Private(Provide(func() greeting { return "private" })),
+				Decorate(func(g greeting) greeting { return g + "!" }),
+			},
+		}
+		_, scopes, err := buildScopes([]Bundle{pools})
+		require.NoError(t, err)
+		var g greeting
+		require.NoError(t, scopes["pools"].Resolve(&g))
+		require.Equal(t, greeting("private!"), g)
+	})
+
+	t.Run("parameters could not be decorated", func(t *testing.T) {
+		type parameters struct {
+			Addr string
+		}
+		b := Bundle{
+			Name:       "bundle",
+			Parameters: []Parameter{&parameters{}},
+			Components: []ComponentOption{
+				Decorate(func(p *parameters) *parameters { return p }),
+			},
+		}
+		_, err := buildContainer([]Bundle{b})
+		require.EqualError(t, err, "decorate *slice.parameters: parameters could not be decorated")
+	})
+
+	t.Run("invalid decorator cause error", func(t *testing.T) {
+		b := Bundle{
+			Name: "bundle",
+			Components: []ComponentOption{
+				Decorate(func(h http.Handler) *http.ServeMux { return nil }),
+			},
+		}
+		_, err := buildContainer([]Bundle{b})
+		require.EqualError(t, err, "decorate: invalid decorator signature, got func(http.Handler) *http.ServeMux")
+	})
+}
+
+// prefixLogger prefixes messages of logger.
+type prefixLogger struct {
+	Logger
+	prefix string
+}
+
+// Printf implements Logger interface.
+func (l prefixLogger) Printf(bundle string, format string, values ...interface{}) {
+	l.Logger.Printf(bundle, l.prefix+format, values...)
+}
+
+func TestDecorate_logger(t *testing.T) {
+	logger := &testcmp.Log{}
+	b := Bundle{
+		Name: "logging",
+		Components: []ComponentOption{
+			Decorate(func(logger Logger) Logger { return prefixLogger{Logger: logger, prefix: "logging: "} }),
+		},
+	}
+	app := New(
+		WithName("app"),
+		WithArgs(),
+		WithEnvLookup(func(key string) (string, bool) { return "", false }),
+		WithLogger(logger),
+		WithBundles(b),
+		WithComponents(Provide(func() Dispatcher {
+			return &testcmp.FuncDispatcher{RunFunc: func(ctx context.Context) error { return nil }}
+		})),
+	)
+	require.NoError(t, app.Start())
+	require.Contains(t, logger.PrintLogs(), "logging: Starting")
+}
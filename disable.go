@@ -0,0 +1,64 @@
+package slice
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultDisableBundles is an environment variable with comma-separated names of disabled bundles.
+const defaultDisableBundles = "SLICE_DISABLE_BUNDLES"
+
+// names is a flag value with comma-separated or repeated names.
+type names []string
+
+// String implements flag.Value interface.
+func (n *names) String() string {
+	return strings.Join(*n, ",")
+}
+
+// Set implements flag.Value interface.
+func (n *names) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			*n = append(*n, name)
+		}
+	}
+	return nil
+}
+
+// disableBundles removes disabled bundles from sorted list. Enabled bundle that depends on disabled
+// bundle causes error. Unknown names are logged if logger is not nil.
+func disableBundles(logger Logger, bundles []Bundle, disabled []string) ([]Bundle, error) {
+	if len(disabled) == 0 {
+		return bundles, nil
+	}
+	off := map[string]bool{}
+	for _, name := range disabled {
+		off[name] = true
+	}
+	removed := map[string]bool{}
+	var enabled []Bundle
+	for _, b := range bundles {
+		if off[b.Name] {
+			removed[b.Name] = true
+			continue
+		}
+		enabled = append(enabled, b)
+	}
+	for _, b := range enabled {
+		for _, dep := range b.Bundles {
+			if removed[dep.Name] {
+				return nil, fmt.Errorf("bundle %s depends on disabled bundle %s", b.Name, dep.Name)
+			}
+		}
+	}
+	if logger != nil {
+		for _, name := range disabled {
+			if off[name] && !removed[name] {
+				logger.Printf("slice", "Disabled bundle %s not found", name)
+				off[name] = false
+			}
+		}
+	}
+	return enabled, nil
+}
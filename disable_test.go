@@ -0,0 +1,36 @@
+package slice
+
+import (
+	"testing"
+
+	"github.com/goava/slice/testcmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisableBundles(t *testing.T) {
+	config := Bundle{Name: "config"}
+	cron := Bundle{Name: "cron", Bundles: []Bundle{config}}
+	api := Bundle{Name: "api", Bundles: []Bundle{config}}
+	sorted := []Bundle{config, cron, api}
+	bundleNames := func(bundles []Bundle) (names []string) {
+		for _, b := range bundles {
+			names = append(names, b.Name)
+		}
+		return names
+	}
+
+	t.Run("disabled bundle removed", func(t *testing.T) {
+		var disabled names
+		require.NoError(t, disabled.Set("cron, unknown"))
+		logger := &testcmp.Log{}
+		bundles, err := disableBundles(logger, sorted, disabled)
+		require.NoError(t, err)
+		require.Equal(t, []string{"config", "api"}, bundleNames(bundles))
+		require.Equal(t, []string{"Disabled bundle unknown not found"}, logger.PrintLogs())
+	})
+
+	t.Run("dependency of enabled bundle causes error", func(t *testing.T) {
+		_, err := disableBundles(nil, sorted, names{"cron", "config"})
+		require.EqualError(t, err, "bundle api depends on disabled bundle config")
+	})
+}
@@ -2,7 +2,6 @@ package slice
 
 import (
 	"fmt"
-	"os"
 	"reflect"
 )
 
@@ -32,8 +31,3 @@ type bundleDIError struct {
 func (p bundleDIError) Error() string {
 	return fmt.Sprintf("%s", p.err)
 }
-
-func printStartError(err error) {
-	fmt.Printf("%s", err)
-	os.Exit(1)
-}
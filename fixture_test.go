@@ -0,0 +1,41 @@
+package slice
+
+import "github.com/goava/di"
+
+// applyBundles applies bundles and component options to a new application.
+func applyBundles(bundles []Bundle, options ...ComponentOption) *Application {
+	app := &Application{}
+	for _, b := range bundles {
+		b.apply(app)
+	}
+	for _, o := range options {
+		o.apply(app)
+	}
+	return app
+}
+
+// bundleComponents returns components of bundles.
+func bundleComponents(bundles ...Bundle) []component {
+	return applyBundles(bundles).components
+}
+
+// buildContainer replaces and decorates components of bundles and options and creates container with them.
+func buildContainer(bundles []Bundle, options ...ComponentOption) (*di.Container, error) {
+	container, _, err := buildScopes(bundles, options...)
+	return container, err
+}
+
+// buildScopes replaces and decorates components of bundles and options and creates application container
+// and containers of bundles with private components.
+func buildScopes(bundles []Bundle, options ...ComponentOption) (*di.Container, map[string]*di.Container, error) {
+	app := applyBundles(bundles, options...)
+	components, err := replace(app.components, app.replacements)
+	if err != nil {
+		return nil, nil, err
+	}
+	components, err = decorate(components, app.decorators, bundles, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return createScopes(nil, components)
+}
@@ -1,6 +1,6 @@
 module github.com/goava/slice
 
-go 1.13
+go 1.14
 
 require (
 	github.com/goava/di v1.11.0
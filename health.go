@@ -0,0 +1,38 @@
+package slice
+
+import "sync"
+
+// Health contains application health information. Optional bundles that failed to boot are
+// reported as degraded.
+type Health struct {
+	lock     sync.RWMutex
+	degraded map[string]error
+}
+
+// Healthy reports whether all bundles booted successfully.
+func (h *Health) Healthy() bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return len(h.degraded) == 0
+}
+
+// Degraded returns boot errors of optional bundles by bundle name.
+func (h *Health) Degraded() map[string]error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	degraded := make(map[string]error, len(h.degraded))
+	for name, err := range h.degraded {
+		degraded[name] = err
+	}
+	return degraded
+}
+
+// degrade marks bundle as degraded.
+func (h *Health) degrade(bundle string, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.degraded == nil {
+		h.degraded = map[string]error{}
+	}
+	h.degraded[bundle] = err
+}
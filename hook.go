@@ -1,6 +1,12 @@
 package slice
 
-import "github.com/goava/di"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goava/di"
+)
 
 // Hook
 type Hook struct {
@@ -8,4 +14,78 @@ type Hook struct {
 	BeforeStart di.Invocation
 	// BeforeShutdown invokes function before application shutdown.
 	BeforeShutdown di.Invocation
+	// Retry sets the retry policy of BeforeStart invocation. If nil, BeforeStart will be invoked once.
+	Retry *RetryPolicy
+}
+
+// RetryPolicy describes how to retry failed BeforeStart hook. Retries are bounded by application
+// start timeout, see StartTimeout(). Hook gets context.Context that is done on start timeout.
+//
+//	slice.Hook{
+//		BeforeStart: ConnectDatabase,
+//		Retry: &slice.RetryPolicy{
+//			MaxAttempts: 10,
+//			Backoff:     100 * time.Millisecond,
+//			MaxBackoff:  time.Second,
+//		},
+//	}
+type RetryPolicy struct {
+	// MaxAttempts limits the number of attempts. Zero means retry until start timeout.
+	MaxAttempts int
+	// Backoff is a delay before the first retry. Each next delay is doubled. Backoff less than
+	// 10ms is raised to 10ms.
+	Backoff time.Duration
+	// MaxBackoff limits the delay between attempts. Zero means no limit.
+	MaxBackoff time.Duration
+}
+
+// minBackoff is a minimal delay between attempts.
+const minBackoff = 10 * time.Millisecond
+
+// do calls fn until it succeeds, attempts are exhausted or context is done.
+func (p RetryPolicy) do(ctx context.Context, logger Logger, clock Clock, bundle string, fn func() error) error {
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		logger.Printf(bundle, "Attempt %d failed: %s", attempt, err)
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+		if backoff < minBackoff {
+			backoff = minBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return &RetryError{Attempts: attempt, Err: err}
+		case <-clock.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// RetryError is returned when BeforeStart hook failed after all attempts.
+type RetryError struct {
+	// Attempts is a number of made attempts.
+	Attempts int
+	// Err is an error of the last attempt.
+	Err error
+}
+
+// Error implements error interface.
+func (e *RetryError) Error() string {
+	if e.Attempts == 1 {
+		return fmt.Sprintf("1 attempt failed: %s", e.Err)
+	}
+	return fmt.Sprintf("%d attempts failed: %s", e.Attempts, e.Err)
+}
+
+// Unwrap returns an error of the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
 }
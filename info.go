@@ -9,4 +9,25 @@ type Info struct {
 	Env Env
 	// The debug flag.
 	Debug bool
+
+	// lookup looks up environment variables, see slice.WithEnvLookup().
+	lookup func(key string) (string, bool)
+	// parse parses parameters of conditions, see slice.IfParameter().
+	parse func(parameter Parameter) error
+}
+
+// lookupEnv looks up environment variable with application lookup function.
+func (i Info) lookupEnv(key string) (string, bool) {
+	if i.lookup != nil {
+		return i.lookup(key)
+	}
+	return lookupEnv(key)
+}
+
+// parseParameter parses parameter with application parameter parser.
+func (i Info) parseParameter(parameter Parameter) error {
+	if i.parse != nil {
+		return i.parse(parameter)
+	}
+	return stdParameterParser{lookup: i.lookup}.Parse("", parameter)
 }
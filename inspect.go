@@ -0,0 +1,99 @@
+package slice
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// printBundles prints bundles in boot order with their metadata, dependencies, parameters, hooks and
+// provided types.
+func printBundles(w io.Writer, bundles []Bundle, components []component) error {
+	present := map[string]bool{}
+	for _, b := range bundles {
+		present[b.Name] = true
+	}
+	provided := map[string][]string{}
+	candidates := consumedTypes(bundles, components)
+	for _, c := range components {
+		for _, t := range c.types(candidates) {
+			name := t.String()
+			if c.private {
+				name += " (private)"
+			}
+			provided[c.bundle] = append(provided[c.bundle], name)
+		}
+	}
+	var b strings.Builder
+	for i, bundle := range bundles {
+		fmt.Fprintf(&b, "%d. %s", i+1, bundle.Name)
+		if bundle.Version != "" {
+			fmt.Fprintf(&b, " %s", bundle.Version)
+		}
+		if bundle.Optional {
+			b.WriteString(" (optional)")
+		}
+		b.WriteString("\n")
+		if bundle.Description != "" {
+			fmt.Fprintf(&b, "   %s\n", bundle.Description)
+		}
+		var deps []string
+		for _, dep := range bundle.Bundles {
+			if present[dep.Name] {
+				deps = append(deps, dep.Name)
+			}
+		}
+		for _, name := range bundle.After {
+			if present[name] {
+				deps = append(deps, name)
+			}
+		}
+		var parameters []string
+		for _, p := range bundle.Parameters {
+			parameters = append(parameters, reflect.TypeOf(p).String())
+		}
+		var hooks []string
+		for _, h := range bundle.Hooks {
+			if h.BeforeStart != nil {
+				hooks = append(hooks, "BeforeStart "+funcName(h.BeforeStart))
+			}
+			if h.BeforeShutdown != nil {
+				hooks = append(hooks, "BeforeShutdown "+funcName(h.BeforeShutdown))
+			}
+		}
+		for _, line := range []struct {
+			key    string
+			values []string
+		}{
+			{"owner", []string{bundle.Owner}},
+			{"url", []string{bundle.URL}},
+			{"depends on", deps},
+			{"parameters", parameters},
+			{"hooks", hooks},
+			{"provides", provided[bundle.Name]},
+		} {
+			if len(line.values) == 0 || line.values[0] == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "   %-12s%s\n", line.key+":", strings.Join(line.values, ", "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// funcName returns short name of function.
+func funcName(fn interface{}) string {
+	rv := reflect.ValueOf(fn)
+	if rv.Kind() != reflect.Func {
+		return rv.Type().String()
+	}
+	f := runtime.FuncForPC(rv.Pointer())
+	if f == nil {
+		return rv.Type().String()
+	}
+	return path.Base(f.Name())
+}
@@ -0,0 +1,50 @@
+package slice
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintBundles(t *testing.T) {
+	config := Bundle{Name: "config"}
+	server := Bundle{
+		Name:        "server",
+		Description: "HTTP server.",
+		Version:     "1.2.0",
+		Owner:       "platform",
+		URL:         "https://example.com/server",
+		Parameters:  []Parameter{&serverParameters{}},
+		Components: []ComponentOption{
+			Private(Provide(http.NewServeMux)),
+			Provide(func(mux *http.ServeMux) *http.Server { return &http.Server{Handler: mux} }),
+		},
+		Hooks: []Hook{{
+			BeforeStart:    startServer,
+			BeforeShutdown: shutdownServer,
+		}},
+		Bundles:  []Bundle{config},
+		After:    []string{"metrics"},
+		Optional: true,
+	}
+	app := &Application{}
+	server.apply(app)
+	var buf bytes.Buffer
+	require.NoError(t, printBundles(&buf, []Bundle{config, server}, app.components))
+	require.Equal(t, `1. config
+2. server 1.2.0 (optional)
+   HTTP server.
+   owner:      platform
+   url:        https://example.com/server
+   depends on: config
+   parameters: *slice.serverParameters
+   hooks:      BeforeStart slice.startServer, BeforeShutdown slice.shutdownServer
+   provides:   *http.ServeMux (private), *http.Server
+`, buf.String())
+}
+
+func startServer(*http.Server) {}
+
+func shutdownServer(*http.Server) {}
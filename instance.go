@@ -0,0 +1,131 @@
+package slice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goava/di"
+)
+
+// Instance creates named instance of bundle. It allows to use the same bundle several times, e.g. public
+// and admin HTTP servers:
+//
+//	slice.WithBundles(
+//		slice.Instance(httpsrv.Bundle, "public"),
+//		slice.Instance(httpsrv.Bundle, "admin"),
+//	)
+//
+// Instance has name "<bundle>.<instance>" and its own copy of parameters. Parameters are parsed
+// with prefix of instance name, e.g. ADMIN_ADDR. All components of instance are provided with
+// di.Tags{"name": "<instance>"}. Instance constructors, hooks and checks get components of the same
+// instance. Consumers choose instance by name:
+//
+//	type Dispatcher struct {
+//		di.Inject
+//		Public *http.Server `di:"name=public"`
+//		Admin  *http.Server `di:"name=admin"`
+//	}
+func Instance(bundle Bundle, name string) Bundle {
+	b := bundle
+	b.Name = fmt.Sprintf("%s.%s", bundle.Name, name)
+	b.instance = name
+	b.Parameters = nil
+	for _, p := range bundle.Parameters {
+		b.Parameters = append(b.Parameters, copyParameter(p))
+	}
+	return b
+}
+
+// copyParameter copies parameter with default values.
+func copyParameter(p Parameter) Parameter {
+	rv := reflect.ValueOf(p)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return p
+	}
+	c := reflect.New(rv.Elem().Type())
+	c.Elem().Set(rv.Elem())
+	return c.Interface()
+}
+
+// instancePrefix returns parameter prefix of bundle instance.
+func instancePrefix(prefix string, instance string) string {
+	if prefix == "" {
+		return strings.ToUpper(instance)
+	}
+	return fmt.Sprintf("%s_%s", prefix, strings.ToUpper(instance))
+}
+
+// instantiate tags components of bundle instances. Dependencies of instance constructors, hooks and checks
+// provided by the same instance are resolved by the instance name. Private components of instance are
+// resolved from the bundle container, see createScopes().
+func instantiate(components []component, bundles []Bundle, container func() *di.Container) ([]component, []Bundle, error) {
+	instances := map[string]map[reflect.Type]bool{}
+	tags := map[string]di.Tags{}
+	for _, b := range bundles {
+		if b.instance == "" {
+			continue
+		}
+		instances[b.Name] = map[reflect.Type]bool{}
+		tags[b.Name] = di.Tags{"name": b.instance}
+		for _, p := range b.Parameters {
+			instances[b.Name][reflect.TypeOf(p)] = true
+		}
+	}
+	if len(instances) == 0 {
+		return components, bundles, nil
+	}
+	candidates := consumedTypes(bundles, components)
+	for _, c := range components {
+		if types, ok := instances[c.bundle]; ok && !c.private {
+			for _, t := range c.types(candidates) {
+				types[t] = true
+			}
+		}
+	}
+	resolver := func(bundle string) func(ptr di.Pointer) error {
+		return func(ptr di.Pointer) error {
+			return container().Resolve(ptr, tags[bundle])
+		}
+	}
+	components = append([]component(nil), components...)
+	for i, c := range components {
+		types, ok := instances[c.bundle]
+		if !ok {
+			continue
+		}
+		if name, ok := c.tags()["name"]; ok {
+			return nil, nil, fmt.Errorf("%s: component %s has name %q, instance components are named by instance", c.bundle, c.result(), name)
+		}
+		c.options = append(append([]di.ProvideOption(nil), c.options...), tags[c.bundle])
+		if !c.supplied {
+			c.constructor = rewire(c.constructor, types, resolver(c.bundle))
+		}
+		components[i] = c
+	}
+	bundles = append([]Bundle(nil), bundles...)
+	for i, b := range bundles {
+		types, ok := instances[b.Name]
+		if !ok {
+			continue
+		}
+		hooks := make([]Hook, len(b.Hooks))
+		for j, h := range b.Hooks {
+			if h.BeforeStart != nil {
+				h.BeforeStart = rewire(h.BeforeStart, types, resolver(b.Name))
+			}
+			if h.BeforeShutdown != nil {
+				h.BeforeShutdown = rewire(h.BeforeShutdown, types, resolver(b.Name))
+			}
+			hooks[j] = h
+		}
+		checks := make([]Check, len(b.Checks))
+		for j, c := range b.Checks {
+			c.Run = rewire(c.Run, types, resolver(b.Name))
+			checks[j] = c
+		}
+		bundles[i].Hooks = hooks
+		bundles[i].Checks = checks
+	}
+	return components, bundles, nil
+}
@@ -0,0 +1,103 @@
+package slice
+
+import (
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+)
+
+type serverParameters struct {
+	Addr string
+}
+
+type server struct {
+	addr string
+}
+
+func TestInstance(t *testing.T) {
+	bundle := Bundle{
+		Name:       "httpsrv",
+		Parameters: []Parameter{&serverParameters{Addr: ":8080"}},
+		Components: []ComponentOption{
+			Provide(func(p *serverParameters) *server { return &server{addr: p.Addr} }),
+		},
+	}
+
+	// serve creates container with public and admin instances of bundle and returns addresses of
+	// their servers, instances listen on :80 and :8081
+	serve := func(t *testing.T, bundle Bundle) (addrs []string) {
+		public, admin := Instance(bundle, "public"), Instance(bundle, "admin")
+		instances := []Bundle{public, admin}
+		var container *di.Container
+		components, _, err := instantiate(bundleComponents(instances...), instances, func() *di.Container { return container })
+		require.NoError(t, err)
+		container, _, err = createScopes(nil, components)
+		require.NoError(t, err)
+		public.Parameters[0].(*serverParameters).Addr = ":80"
+		admin.Parameters[0].(*serverParameters).Addr = ":8081"
+		for _, set := range collectParameters("", nil, instances) {
+			for _, p := range set.parameters {
+				require.NoError(t, container.ProvideValue(p, set.tags))
+			}
+		}
+		for _, name := range []string{"public", "admin"} {
+			var s *server
+			require.NoError(t, container.Resolve(&s, di.Tags{"name": name}))
+			addrs = append(addrs, s.addr)
+		}
+		return addrs
+	}
+
+	t.Run("instance has its own name and parameters", func(t *testing.T) {
+		admin := Instance(bundle, "admin")
+		require.Equal(t, "httpsrv.admin", admin.Name)
+		require.Equal(t, "admin", admin.instance)
+		require.False(t, bundle.Parameters[0] == admin.Parameters[0])
+		require.Equal(t, bundle.Parameters[0], admin.Parameters[0])
+	})
+
+	t.Run("instance components resolved by name", func(t *testing.T) {
+		require.Equal(t, []string{":80", ":8081"}, serve(t, bundle))
+	})
+
+	t.Run("instance private components resolved from bundle", func(t *testing.T) {
+		type pool struct{ addr string }
+		bundle := Bundle{
+			Name:       "httpsrv",
+			Parameters: []Parameter{&serverParameters{}},
+			Components: []ComponentOption{
+				Private(Provide(func(p *serverParameters) *pool { return &pool{addr: p.Addr} })),
+				Provide(func(p *pool) *server { return &server{addr: p.addr} }),
+			},
+		}
+		require.Equal(t, []string{":80", ":8081"}, serve(t, bundle))
+	})
+
+	t.Run("named instance component causes error", func(t *testing.T) {
+		named := Bundle{
+			Name: "httpsrv",
+			Components: []ComponentOption{
+				Provide(func() *server { return &server{} }, di.WithName("main")),
+			},
+		}
+		admin := Instance(named, "admin")
+		_, _, err := instantiate(bundleComponents(admin), []Bundle{admin}, func() *di.Container { return nil })
+		require.EqualError(t, err, `httpsrv.admin: component *slice.server has name "main", instance components are named by instance`)
+	})
+
+	t.Run("instance included twice is the same bundle", func(t *testing.T) {
+		sorted, err := prepareBundles([]Bundle{Instance(bundle, "admin"), Instance(bundle, "admin")}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"httpsrv.admin"}, bundleNames(sorted).Names())
+	})
+
+	t.Run("instance parameters have own prefix", func(t *testing.T) {
+		sets := collectParameters("APP", []Parameter{&serverParameters{}}, []Bundle{bundle, Instance(bundle, "admin")})
+		require.Len(t, sets, 2)
+		require.Equal(t, "APP", sets[0].prefix)
+		require.Len(t, sets[0].parameters, 2)
+		require.Equal(t, "APP_ADMIN", sets[1].prefix)
+		require.Equal(t, di.Tags{"name": "admin"}, sets[1].tags)
+	})
+}
@@ -0,0 +1,38 @@
+package slice
+
+import (
+	"context"
+
+	"github.com/goava/slice/internal/intercept"
+)
+
+func init() {
+	intercept.Option = func(interceptor intercept.Interceptor) interface{} {
+		return option(func(s *Application) {
+			s.interceptors = append(s.interceptors, interceptor)
+		})
+	}
+}
+
+// observer reports lifecycle steps to tracer and interceptors.
+type observer struct {
+	tracer       Tracer
+	interceptors []intercept.Interceptor
+}
+
+// Trace implements Tracer interface.
+func (o observer) Trace(step string, bundle string, name string) {
+	if o.tracer != nil {
+		o.tracer.Trace(step, bundle, name)
+	}
+}
+
+// intercept calls interceptors until the first error.
+func (o observer) intercept(ctx context.Context, step string, bundle string, name string) error {
+	for _, interceptor := range o.interceptors {
+		if err := interceptor(ctx, step, bundle, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
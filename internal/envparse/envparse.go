@@ -0,0 +1,268 @@
+// Package envparse parses parameters from environment variables that are looked up with a function.
+// It follows the rules of github.com/kelseyhightower/envconfig, which reads the process environment
+// only: envconfig, default, required, split_words and ignored tags, nested structures, decoders
+// and comma-separated slices and maps.
+package envparse
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// Lookup looks up environment variable.
+type Lookup func(key string) (string, bool)
+
+var (
+	gatherRegexp  = regexp.MustCompile("([^A-Z]+|[A-Z]+[^A-Z]+|[A-Z]+)")
+	acronymRegexp = regexp.MustCompile("([A-Z]+)([A-Z][^A-Z]+)")
+)
+
+// variable is a field of specification with its environment variable.
+type variable struct {
+	name  string
+	alt   string
+	key   string
+	field reflect.Value
+	tags  reflect.StructTag
+}
+
+// Process populates specification with environment variables looked up by lookup.
+func Process(lookup Lookup, prefix string, spec interface{}) error {
+	variables, err := gather(prefix, spec)
+	if err != nil {
+		return err
+	}
+	for _, v := range variables {
+		value, ok := lookup(v.key)
+		if !ok && v.alt != "" {
+			value, ok = lookup(v.alt)
+		}
+		def := v.tags.Get("default")
+		if def != "" && !ok {
+			value = def
+		}
+		if !ok && def == "" {
+			if isTrue(v.tags.Get("required")) {
+				key := v.key
+				if v.alt != "" {
+					key = v.alt
+				}
+				return fmt.Errorf("required key %s missing value", key)
+			}
+			continue
+		}
+		if err := set(value, v.field); err != nil {
+			return &envconfig.ParseError{
+				KeyName:   v.key,
+				FieldName: v.name,
+				TypeName:  v.field.Type().String(),
+				Value:     value,
+				Err:       err,
+			}
+		}
+	}
+	return nil
+}
+
+// gather collects variables of specification fields.
+func gather(prefix string, spec interface{}) ([]variable, error) {
+	s := reflect.ValueOf(spec)
+	if s.Kind() != reflect.Ptr || s.Elem().Kind() != reflect.Struct {
+		return nil, envconfig.ErrInvalidSpecification
+	}
+	s = s.Elem()
+	var variables []variable
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		ftype := s.Type().Field(i)
+		if !f.CanSet() || isTrue(ftype.Tag.Get("ignored")) {
+			continue
+		}
+		for f.Kind() == reflect.Ptr {
+			if f.IsNil() {
+				if f.Type().Elem().Kind() != reflect.Struct {
+					break
+				}
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+			f = f.Elem()
+		}
+		v := variable{
+			name:  ftype.Name,
+			alt:   strings.ToUpper(ftype.Tag.Get("envconfig")),
+			key:   ftype.Name,
+			field: f,
+			tags:  ftype.Tag,
+		}
+		if isTrue(ftype.Tag.Get("split_words")) {
+			if key := splitWords(ftype.Name); key != "" {
+				v.key = key
+			}
+		}
+		if v.alt != "" {
+			v.key = v.alt
+		}
+		if prefix != "" {
+			v.key = fmt.Sprintf("%s_%s", prefix, v.key)
+		}
+		v.key = strings.ToUpper(v.key)
+		if f.Kind() == reflect.Struct && decoder(f) == nil {
+			inner := prefix
+			if !ftype.Anonymous {
+				inner = v.key
+			}
+			nested, err := gather(inner, f.Addr().Interface())
+			if err != nil {
+				return nil, err
+			}
+			variables = append(variables, nested...)
+			continue
+		}
+		variables = append(variables, v)
+	}
+	return variables, nil
+}
+
+// splitWords joins camel case words of name with underscore.
+func splitWords(name string) string {
+	var words []string
+	for _, match := range gatherRegexp.FindAllStringSubmatch(name, -1) {
+		if m := acronymRegexp.FindStringSubmatch(match[0]); len(m) == 3 {
+			words = append(words, m[1], m[2])
+			continue
+		}
+		words = append(words, match[0])
+	}
+	return strings.Join(words, "_")
+}
+
+// set converts value to field type and sets field.
+func set(value string, field reflect.Value) error {
+	if decode := decoder(field); decode != nil {
+		return decode(value)
+	}
+	typ := field.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		if field.IsNil() {
+			field.Set(reflect.New(typ))
+		}
+		field = field.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if typ == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 0, typ.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, typ.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, typ.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		sl := reflect.MakeSlice(typ, 0, 0)
+		if typ.Elem().Kind() == reflect.Uint8 {
+			sl = reflect.ValueOf([]byte(value))
+		} else if strings.TrimSpace(value) != "" {
+			values := strings.Split(value, ",")
+			sl = reflect.MakeSlice(typ, len(values), len(values))
+			for i, v := range values {
+				if err := set(v, sl.Index(i)); err != nil {
+					return err
+				}
+			}
+		}
+		field.Set(sl)
+	case reflect.Map:
+		m := reflect.MakeMap(typ)
+		if strings.TrimSpace(value) != "" {
+			for _, pair := range strings.Split(value, ",") {
+				kv := strings.Split(pair, ":")
+				if len(kv) != 2 {
+					return fmt.Errorf("invalid map item: %q", pair)
+				}
+				k := reflect.New(typ.Key()).Elem()
+				if err := set(kv[0], k); err != nil {
+					return err
+				}
+				v := reflect.New(typ.Elem()).Elem()
+				if err := set(kv[1], v); err != nil {
+					return err
+				}
+				m.SetMapIndex(k, v)
+			}
+		}
+		field.Set(m)
+	}
+	return nil
+}
+
+// decoder returns decoding method of field or its address. Methods are checked in order of
+// envconfig: Decode, Set, UnmarshalText and UnmarshalBinary.
+func decoder(field reflect.Value) func(value string) error {
+	if !field.CanInterface() {
+		return nil
+	}
+	candidates := []interface{}{field.Interface()}
+	if field.CanAddr() {
+		candidates = append(candidates, field.Addr().Interface())
+	}
+	for _, c := range candidates {
+		if d, ok := c.(envconfig.Decoder); ok {
+			return d.Decode
+		}
+	}
+	for _, c := range candidates {
+		if s, ok := c.(envconfig.Setter); ok {
+			return s.Set
+		}
+	}
+	for _, c := range candidates {
+		if u, ok := c.(encoding.TextUnmarshaler); ok {
+			return func(value string) error { return u.UnmarshalText([]byte(value)) }
+		}
+	}
+	for _, c := range candidates {
+		if u, ok := c.(encoding.BinaryUnmarshaler); ok {
+			return func(value string) error { return u.UnmarshalBinary([]byte(value)) }
+		}
+	}
+	return nil
+}
+
+func isTrue(s string) bool {
+	b, _ := strconv.ParseBool(s)
+	return b
+}
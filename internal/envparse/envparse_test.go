@@ -0,0 +1,300 @@
+package envparse
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type nested struct {
+	Host string `default:"localhost"`
+	Port int    `default:"5432"`
+}
+
+type Embedded struct {
+	Level string
+}
+
+type specification struct {
+	Embedded
+	Name         string        `envconfig:"name"`
+	Timeout      time.Duration `default:"1s"`
+	MaxRetries   uint8         `split_words:"true"`
+	Enabled      *bool
+	Ratio        float64
+	Tags         []string
+	Limits       map[string]int
+	IP           net.IP
+	Database     nested
+	Skipped      string `ignored:"true"`
+	Unused       string
+	RequiredWith string `default:"x" required:"true"`
+}
+
+func TestProcess(t *testing.T) {
+	env := map[string]string{
+		"APP_NAME":          "sliced",
+		"APP_LEVEL":         "debug",
+		"APP_MAX_RETRIES":   "3",
+		"APP_ENABLED":       "true",
+		"APP_RATIO":         "0.5",
+		"APP_TAGS":          "a,b",
+		"APP_LIMITS":        "a:1,b:2",
+		"APP_IP":            "127.0.0.1",
+		"APP_DATABASE_HOST": "db",
+		"APP_SKIPPED":       "skipped",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	t.Run("the same result as envconfig", func(t *testing.T) {
+		for k, v := range env {
+			require.NoError(t, os.Setenv(k, v))
+			defer os.Unsetenv(k)
+		}
+		var want, got specification
+		require.NoError(t, envconfig.Process("app", &want))
+		require.NoError(t, Process(lookup, "APP", &got))
+		require.Equal(t, want, got)
+		require.Equal(t, "sliced", got.Name)
+		require.Equal(t, "db", got.Database.Host)
+		require.Equal(t, 5432, got.Database.Port)
+		require.Equal(t, uint8(3), got.MaxRetries)
+	})
+
+	t.Run("required value missing", func(t *testing.T) {
+		var spec struct {
+			Addr string `required:"true"`
+		}
+		require.EqualError(t, Process(lookup, "APP", &spec), "required key APP_ADDR missing value")
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		var spec struct {
+			Name int `envconfig:"name"`
+		}
+		err := Process(lookup, "APP", &spec)
+		require.IsType(t, &envconfig.ParseError{}, err)
+	})
+
+	t.Run("specification must be a pointer", func(t *testing.T) {
+		require.Equal(t, envconfig.ErrInvalidSpecification, Process(lookup, "", specification{}))
+	})
+}
+
+type level int
+
+// Decode implements envconfig.Decoder interface.
+func (l *level) Decode(value string) error {
+	switch value {
+	case "debug":
+		*l = 1
+	case "info":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", value)
+	}
+	return nil
+}
+
+type names []string
+
+// Set implements envconfig.Setter interface.
+func (n *names) Set(value string) error {
+	*n = strings.Split(value, ";")
+	return nil
+}
+
+type host string
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (h *host) UnmarshalText(text []byte) error {
+	*h = host(strings.ToLower(string(text)))
+	return nil
+}
+
+// TestProcess_envconfig runs envconfig and envparse on the same specifications and environments.
+func TestProcess_envconfig(t *testing.T) {
+	type database struct {
+		Host string `default:"localhost"`
+		Port int    `default:"5432"`
+	}
+	cases := []struct {
+		name   string
+		prefix string
+		env    map[string]string
+		spec   func() interface{}
+	}{
+		{
+			name: "defaults",
+			spec: func() interface{} {
+				return &struct {
+					Addr    string        `default:":8080"`
+					Timeout time.Duration `default:"1s"`
+					Enabled bool          `default:"true"`
+				}{}
+			},
+		},
+		{
+			name:   "prefix and split words",
+			prefix: "app",
+			env:    map[string]string{"APP_MAX_RETRIES": "3", "APP_MAXRETRIES": "5", "APP_HTTP_SERVER_ADDR": ":80"},
+			spec: func() interface{} {
+				return &struct {
+					MaxRetries     uint8  `split_words:"true"`
+					HTTPServerAddr string `split_words:"true"`
+				}{}
+			},
+		},
+		{
+			name:   "envconfig tag without prefix fallback",
+			prefix: "app",
+			env:    map[string]string{"ADDR": ":80", "APP_NAME": "sliced"},
+			spec: func() interface{} {
+				return &struct {
+					Addr string `envconfig:"addr"`
+					Name string `envconfig:"name"`
+				}{}
+			},
+		},
+		{
+			name:   "nested and embedded structures",
+			prefix: "app",
+			env:    map[string]string{"APP_DATABASE_HOST": "db", "APP_LEVEL": "debug", "APP_REPLICA_PORT": "6432"},
+			spec: func() interface{} {
+				return &struct {
+					Embedded
+					Database database
+					Replica  *database
+				}{}
+			},
+		},
+		{
+			name: "slices, maps and pointers",
+			env:  map[string]string{"TAGS": "a,b", "PORTS": "1,2", "LIMITS": "a:1,b:2", "RATIO": "0.5", "ENABLED": "true", "EMPTY": ""},
+			spec: func() interface{} {
+				return &struct {
+					Tags    []string
+					Ports   []int
+					Limits  map[string]int
+					Ratio   *float64
+					Enabled *bool
+					Empty   []string
+					Bytes   []byte
+				}{}
+			},
+		},
+		{
+			name: "decoders",
+			env:  map[string]string{"LEVEL": "info", "NAMES": "a;b", "HOST": "LOCALHOST", "IP": "127.0.0.1"},
+			spec: func() interface{} {
+				return &struct {
+					Level level
+					Names names
+					Host  host
+					IP    net.IP
+				}{}
+			},
+		},
+		{
+			name: "ignored and unexported fields",
+			env:  map[string]string{"SKIPPED": "skipped", "HIDDEN": "hidden", "SHOWN": "shown"},
+			spec: func() interface{} {
+				return &struct {
+					Skipped string `ignored:"true"`
+					hidden  string
+					Shown   string
+				}{}
+			},
+		},
+		{
+			name: "required value missing",
+			spec: func() interface{} {
+				return &struct {
+					Addr string `required:"true"`
+				}{}
+			},
+		},
+		{
+			name: "required value with envconfig tag missing",
+			spec: func() interface{} {
+				return &struct {
+					Addr string `envconfig:"listen_addr" required:"true"`
+				}{}
+			},
+		},
+		{
+			name: "parse error",
+			env:  map[string]string{"PORT": "http"},
+			spec: func() interface{} {
+				return &struct {
+					Port int
+				}{}
+			},
+		},
+		{
+			name: "decoder error",
+			env:  map[string]string{"LEVEL": "trace"},
+			spec: func() interface{} {
+				return &struct {
+					Level level
+				}{}
+			},
+		},
+		{
+			name: "invalid map item",
+			env:  map[string]string{"LIMITS": "a"},
+			spec: func() interface{} {
+				return &struct {
+					Limits map[string]int
+				}{}
+			},
+		},
+		{
+			name: "specification is not a pointer",
+			spec: func() interface{} { return struct{}{} },
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for k, v := range c.env {
+				require.NoError(t, os.Setenv(k, v))
+			}
+			defer func() {
+				for k := range c.env {
+					_ = os.Unsetenv(k)
+				}
+			}()
+			want, got := c.spec(), c.spec()
+			wantErr := envconfig.Process(c.prefix, want)
+			gotErr := Process(func(key string) (string, bool) {
+				v, ok := c.env[key]
+				return v, ok
+			}, c.prefix, got)
+			require.Equal(t, fmt.Sprint(wantErr), fmt.Sprint(gotErr))
+			require.Equal(t, want, got)
+			if wantErr != nil {
+				return
+			}
+			// keys printed by envconfig usage are the keys that are looked up
+			var usage bytes.Buffer
+			require.NoError(t, envconfig.Usagef(c.prefix, c.spec(), &usage, "{{range .}}{{usage_key .}}\n{{end}}"))
+			variables, err := gather(c.prefix, c.spec())
+			require.NoError(t, err)
+			var keys []string
+			for _, v := range variables {
+				keys = append(keys, v.key)
+			}
+			require.Equal(t, strings.Fields(usage.String()), keys)
+		})
+	}
+}
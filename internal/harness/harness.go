@@ -0,0 +1,69 @@
+// Package harness contains parts shared by bundletest and slicetest packages.
+package harness
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/goava/di"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/internal/envparse"
+)
+
+// DispatcherFunc is a function that implements slice.Dispatcher interface.
+type DispatcherFunc func(ctx context.Context) error
+
+// Run implements slice.Dispatcher interface.
+func (f DispatcherFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Probe returns constructor of dispatcher that sends application container to running channel and
+// waits for application stop.
+func Probe(running chan<- *di.Container) func(container *di.Container) slice.Dispatcher {
+	return func(container *di.Container) slice.Dispatcher {
+		return DispatcherFunc(func(ctx context.Context) error {
+			running <- container
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+}
+
+// ParameterParser copies supplied parameter values. Parameters that are not supplied are parsed
+// with Lookup or keep their default values if Lookup is nil.
+type ParameterParser struct {
+	Values []slice.Parameter
+	Lookup envparse.Lookup
+}
+
+// Parse implements slice.ParameterParser interface.
+func (p ParameterParser) Parse(prefix string, parameters ...slice.Parameter) error {
+	for _, parameter := range parameters {
+		target := reflect.ValueOf(parameter)
+		if target.Kind() != reflect.Ptr || target.IsNil() {
+			return fmt.Errorf("parameter %T must be a pointer", parameter)
+		}
+		supplied := false
+		for _, value := range p.Values {
+			if reflect.TypeOf(value) == target.Type() {
+				target.Elem().Set(reflect.ValueOf(value).Elem())
+				supplied = true
+			}
+		}
+		if supplied || p.Lookup == nil {
+			continue
+		}
+		if err := envparse.Process(p.Lookup, prefix, parameter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Usage implements slice.ParameterParser interface.
+func (p ParameterParser) Usage(prefix string, parameters ...slice.Parameter) error {
+	return nil
+}
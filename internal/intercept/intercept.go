@@ -0,0 +1,12 @@
+// Package intercept connects test packages with lifecycle steps of slice application. It is not
+// a part of public API: interceptors are intended for fault injection in tests only.
+package intercept
+
+import "context"
+
+// Interceptor is called before lifecycle steps: BeforeStart and BeforeShutdown hooks and dispatcher
+// start. Error returned by interceptor fails the step.
+type Interceptor func(ctx context.Context, step string, bundle string, name string) error
+
+// Option returns slice.Option that adds interceptor to application. It is set by slice package.
+var Option func(interceptor Interceptor) interface{}
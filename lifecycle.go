@@ -24,35 +24,81 @@ func createContainer(diopts ...di.Option) (*di.Container, error) {
 
 // before is a step of application bootstrap. It iterates over all registered bundles and call their Boot()
 // method. If bundle boot are success shutdown function will be returned in shutdowns. In case, that boot
-// failed process of booting application will be stopped.
-func beforeStart(ctx context.Context, container *di.Container, bundles ...Bundle) (after []hook, _ error) {
-	var errs startErrors
+// failed process of booting application will be stopped and shutdown hooks of booted bundles are returned
+// for rollback. Failure of optional bundle degrades health and skips its shutdown hooks instead.
+func beforeStart(ctx context.Context, container *di.Container, logger Logger, clock Clock, obs observer, health *Health, bundles ...Bundle) (after []hook, _ error) {
 	for _, bundle := range bundles {
 		if err := ctx.Err(); err != nil {
 			return after, fmt.Errorf("boot %s bundle failed: %w", bundle.Name, err)
 		}
+		var hooks []hook
+		var optionalErr error
 		for _, h := range bundle.Hooks {
 			if h.BeforeStart != nil {
-				if err := container.Invoke(h.BeforeStart); err != nil {
-					errs = append(errs, fmt.Errorf("boot %s bundle failed: %w", bundle.Name, err))
+				obs.Trace(StepBoot, bundle.Name, funcName(h.BeforeStart))
+				if err := invokeBeforeStart(ctx, bundle.scope(container), logger, clock, obs, bundle.Name, h); err != nil {
+					if bundle.Optional {
+						optionalErr = err
+						break
+					}
+					return after, startErrors{fmt.Errorf("boot %s bundle failed: %w", bundle.Name, err)}
 				}
 				if h.BeforeShutdown != nil {
-					after = append(after, hook{
-						name: bundle.Name,
-						hook: h.BeforeShutdown,
+					hooks = append(hooks, hook{
+						name:      bundle.Name,
+						hook:      h.BeforeShutdown,
+						container: bundle.container,
 					})
 				}
 			}
 		}
-	}
-	if len(errs) != 0 {
-		return nil, errs
+		if optionalErr != nil {
+			logger.Printf(bundle.Name, "Optional bundle failed: %s", optionalErr)
+			health.degrade(bundle.Name, optionalErr)
+			continue
+		}
+		after = append(after, hooks...)
 	}
 	return after, nil
 }
 
+// invokeBeforeStart invokes BeforeStart hook with respect of its retry policy. Hook gets start context
+// instead of application context, so its invocation is bounded by start timeout.
+func invokeBeforeStart(ctx context.Context, container *di.Container, logger Logger, clock Clock, obs observer, bundle string, h Hook) error {
+	fn := rewire(h.BeforeStart, map[reflect.Type]bool{contextType: true}, func(ptr di.Pointer) error {
+		*ptr.(*context.Context) = ctx
+		return nil
+	})
+	invoke := func() error {
+		return recovered(func() error {
+			if err := obs.intercept(ctx, StepBoot, bundle, funcName(h.BeforeStart)); err != nil {
+				return err
+			}
+			return container.Invoke(fn)
+		})
+	}
+	if h.Retry == nil {
+		return invoke()
+	}
+	return h.Retry.do(ctx, logger, clock, bundle, invoke)
+}
+
+// recovered calls fn and returns its panic as error. Panics of hooks, constructors and dispatchers fail
+// the lifecycle step as errors do, e.g. booted bundles are shut down.
+func recovered(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// contextType is a type of context.Context.
+var contextType = reflect.TypeOf(new(context.Context)).Elem()
+
 // dispatch is a part of application lifecycle. It resolves application dispatcher via container and call Run() method.
-func dispatch(ctx context.Context, logger Logger, stop func(), dispatchers []Dispatcher) error {
+func dispatch(ctx context.Context, logger Logger, obs observer, stop func(), dispatchers []Dispatcher) error {
 	var once sync.Once
 	// start all dispatchers
 	var workers run.Group
@@ -61,7 +107,15 @@ func dispatch(ctx context.Context, logger Logger, stop func(), dispatchers []Dis
 		dt := reflect.TypeOf(dispatcher)
 		execute := func() error {
 			logger.Printf("slice", "Start %s", dt)
-			if err := dispatcher.Run(ctx); err != nil {
+			obs.Trace(StepStart, "slice", dt.String())
+			err := recovered(func() error {
+				if err := obs.intercept(ctx, StepStart, "slice", dt.String()); err != nil {
+					return err
+				}
+				return dispatcher.Run(ctx)
+			})
+			obs.Trace(StepStop, "slice", dt.String())
+			if err != nil {
 				return fmt.Errorf("%s: %w", dt, err)
 			}
 			once.Do(func() {
@@ -85,7 +139,7 @@ func dispatch(ctx context.Context, logger Logger, stop func(), dispatchers []Dis
 }
 
 // beforeShutdown invoke hooks in reverse order.
-func beforeShutdown(ctx context.Context, container *di.Container, hooks []hook) error {
+func beforeShutdown(ctx context.Context, container *di.Container, obs observer, hooks []hook) error {
 	done := make(chan struct{})
 	var errs errShutdown
 	go func() {
@@ -93,7 +147,18 @@ func beforeShutdown(ctx context.Context, container *di.Container, hooks []hook)
 		for i := len(hooks) - 1; i >= 0; i-- {
 			// bundle shutdown
 			h := hooks[i]
-			if err := container.Invoke(h.hook); err != nil {
+			scope := container
+			if h.container != nil {
+				scope = h.container
+			}
+			obs.Trace(StepShutdown, h.name, funcName(h.hook))
+			err := recovered(func() error {
+				if err := obs.intercept(ctx, StepShutdown, h.name, funcName(h.hook)); err != nil {
+					return err
+				}
+				return scope.Invoke(h.hook)
+			})
+			if err != nil {
 				errs = append(errs, fmt.Errorf("shutdown %s failed: %w", h.name, err))
 			}
 		}
@@ -113,6 +178,8 @@ func beforeShutdown(ctx context.Context, container *di.Container, hooks []hook)
 type hook struct {
 	name string
 	hook di.Invocation
+	// container of bundle with private components
+	container *di.Container
 }
 
 type errShutdown []error
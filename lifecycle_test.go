@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/goava/di"
+	"github.com/goava/slice/testcmp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -57,7 +58,7 @@ func TestLifecycle_before(t *testing.T) {
 				},
 			}},
 		}
-		shutdowns, err := beforeStart(context.Background(), c, firstBundle, secondBundle)
+		shutdowns, err := beforeStart(context.Background(), c, &stdLogger{}, realClock{}, observer{}, &Health{}, firstBundle, secondBundle)
 		require.NoError(t, err)
 		require.Len(t, shutdowns, 1)
 		require.Equal(t, []string{"first-bundle", "second-bundle"}, order)
@@ -73,11 +74,61 @@ func TestLifecycle_before(t *testing.T) {
 				BeforeStart: func() error { return errors.New("unexpected error") },
 			}},
 		}
-		hooks, err := beforeStart(context.Background(), c, bundle)
+		hooks, err := beforeStart(context.Background(), c, &stdLogger{}, realClock{}, observer{}, &Health{}, bundle)
 		require.EqualError(t, err, "- boot error-bundle bundle failed: unexpected error\n")
 		require.Len(t, hooks, 0)
 	})
 
+	t.Run("boot stops at the first failed bundle", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		var order []string
+		booted := Bundle{
+			Name: "booted",
+			Hooks: []Hook{{
+				BeforeStart:    func() { order = append(order, "booted") },
+				BeforeShutdown: func() {},
+			}},
+		}
+		failed := Bundle{
+			Name: "failed",
+			Hooks: []Hook{{
+				BeforeStart:    func() error { return errors.New("unexpected error") },
+				BeforeShutdown: func() {},
+			}},
+		}
+		skipped := Bundle{
+			Name: "skipped",
+			Hooks: []Hook{{
+				BeforeStart: func() { order = append(order, "skipped") },
+			}},
+		}
+		hooks, err := beforeStart(context.Background(), c, &stdLogger{}, realClock{}, observer{}, &Health{}, booted, failed, skipped)
+		require.EqualError(t, err, "- boot failed bundle failed: unexpected error\n")
+		require.Equal(t, []string{"booted"}, order)
+		require.Len(t, hooks, 1)
+		require.Equal(t, "booted", hooks[0].name)
+	})
+
+	t.Run("hook panic returned as error", func(t *testing.T) {
+		c, err := di.New(di.Provide(func() *http.ServeMux { panic("unexpected panic") }))
+		require.NoError(t, err)
+		booted := Bundle{
+			Name: "booted",
+			Hooks: []Hook{{
+				BeforeStart:    func() {},
+				BeforeShutdown: func() {},
+			}},
+		}
+		failed := Bundle{
+			Name:  "failed",
+			Hooks: []Hook{{BeforeStart: func(mux *http.ServeMux) {}}},
+		}
+		hooks, err := beforeStart(context.Background(), c, &stdLogger{}, realClock{}, observer{}, &Health{}, booted, failed)
+		require.EqualError(t, err, "- boot failed bundle failed: panic: unexpected panic\n")
+		require.Len(t, hooks, 1)
+	})
+
 	t.Run("shutdowns correct on context cancel", func(t *testing.T) {
 		c, err := di.New()
 		require.NoError(t, err)
@@ -100,10 +151,150 @@ func TestLifecycle_before(t *testing.T) {
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		hooks, err := beforeStart(ctx, c, firstBundle, secondBundle)
+		hooks, err := beforeStart(ctx, c, &stdLogger{}, realClock{}, observer{}, &Health{}, firstBundle, secondBundle)
 		require.EqualError(t, err, "boot first-bundle bundle failed: context canceled")
 		require.Len(t, hooks, 0)
 	})
+
+	t.Run("hook retried until success", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		attempts := 0
+		bundle := Bundle{
+			Name: "retry-bundle",
+			Hooks: []Hook{{
+				BeforeStart: func() error {
+					attempts++
+					if attempts < 3 {
+						return errors.New("not ready")
+					}
+					return nil
+				},
+				Retry: &RetryPolicy{Backoff: time.Millisecond},
+			}},
+		}
+		_, err = beforeStart(context.Background(), c, &stdLogger{}, realClock{}, observer{}, &Health{}, bundle)
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("retry error contains attempt count", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		bundle := Bundle{
+			Name: "retry-bundle",
+			Hooks: []Hook{{
+				BeforeStart: func() error { return errors.New("not ready") },
+				Retry: &RetryPolicy{
+					MaxAttempts: 3,
+					Backoff:     time.Millisecond,
+				},
+			}},
+		}
+		_, err = beforeStart(context.Background(), c, &stdLogger{}, realClock{}, observer{}, &Health{}, bundle)
+		require.EqualError(t, err, "- boot retry-bundle bundle failed: 3 attempts failed: not ready\n")
+		var retryErr *RetryError
+		require.True(t, errors.As(err.(startErrors)[0], &retryErr))
+		require.Equal(t, 3, retryErr.Attempts)
+	})
+
+	t.Run("retry bounded by start timeout", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		attempts := 0
+		bundle := Bundle{
+			Name: "retry-bundle",
+			Hooks: []Hook{{
+				BeforeStart: func() error {
+					attempts++
+					return errors.New("not ready")
+				},
+				Retry: &RetryPolicy{Backoff: time.Hour},
+			}},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = beforeStart(ctx, c, &stdLogger{}, realClock{}, observer{}, &Health{}, bundle)
+		require.EqualError(t, err, "- boot retry-bundle bundle failed: 1 attempt failed: not ready\n")
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("retry backoff is at least 10ms", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		bundle := Bundle{
+			Name: "retry-bundle",
+			Hooks: []Hook{{
+				BeforeStart: func() error { return errors.New("not ready") },
+				Retry:       &RetryPolicy{MaxAttempts: 3},
+			}},
+		}
+		clock := &delayClock{}
+		_, err = beforeStart(context.Background(), c, &stdLogger{}, clock, observer{}, &Health{}, bundle)
+		require.Error(t, err)
+		require.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, clock.delays)
+	})
+
+	t.Run("hook gets start context", func(t *testing.T) {
+		c, err := di.New(
+			di.Provide(func() context.Context { return context.Background() }),
+		)
+		require.NoError(t, err)
+		bundle := Bundle{
+			Name: "slow-bundle",
+			Hooks: []Hook{{
+				BeforeStart: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			}},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = beforeStart(ctx, c, &stdLogger{}, realClock{}, observer{}, &Health{}, bundle)
+		require.EqualError(t, err, "- boot slow-bundle bundle failed: context deadline exceeded\n")
+	})
+
+	t.Run("optional bundle failure degrades health", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		var order []string
+		optionalBundle := Bundle{
+			Name:     "optional-bundle",
+			Optional: true,
+			Hooks: []Hook{
+				{
+					BeforeStart:    func() {},
+					BeforeShutdown: func() {},
+				},
+				{
+					BeforeStart: func() error { return errors.New("unexpected error") },
+				},
+				{
+					BeforeStart: func() {
+						order = append(order, "optional-bundle")
+					},
+				},
+			},
+		}
+		secondBundle := Bundle{
+			Name: "second-bundle",
+			Hooks: []Hook{{
+				BeforeStart: func() {
+					order = append(order, "second-bundle")
+				},
+				BeforeShutdown: func() {},
+			}},
+		}
+		health := &Health{}
+		hooks, err := beforeStart(context.Background(), c, &stdLogger{}, realClock{}, observer{}, health, optionalBundle, secondBundle)
+		require.NoError(t, err)
+		require.Equal(t, []string{"second-bundle"}, order)
+		require.Len(t, hooks, 1)
+		require.Equal(t, "second-bundle", hooks[0].name)
+		require.False(t, health.Healthy())
+		require.EqualError(t, health.Degraded()["optional-bundle"], "unexpected error")
+	})
 }
 
 func TestLifecycle_dispatch(t *testing.T) {
@@ -114,7 +305,7 @@ func TestLifecycle_dispatch(t *testing.T) {
 			},
 		}
 		ctx, cancel := context.WithCancel(context.Background())
-		err := dispatch(ctx, &stdLogger{}, cancel, []Dispatcher{dispatcher})
+		err := dispatch(ctx, &stdLogger{}, observer{}, cancel, []Dispatcher{dispatcher})
 		require.NoError(t, err)
 		require.Len(t, dispatcher.RunCalls(), 1)
 	})
@@ -153,11 +344,25 @@ func TestLifecycle_dispatch(t *testing.T) {
 		}
 
 		ctx, cancel := context.WithCancel(context.Background())
-		err := dispatch(ctx, &stdLogger{}, cancel, []Dispatcher{d1, d2})
+		err := dispatch(ctx, &stdLogger{}, observer{}, cancel, []Dispatcher{d1, d2})
 		require.EqualError(t, err, "failure: *slice.DispatcherMock: unexpected error")
 		require.Len(t, d1.RunCalls(), 1)
 		require.True(t, contextCancelled)
 	})
+
+	t.Run("panic of lazily resolved constructor causes error", func(t *testing.T) {
+		c, err := di.New(di.Provide(func() *http.ServeMux { panic("unexpected panic") }))
+		require.NoError(t, err)
+		dispatcher := &DispatcherMock{
+			RunFunc: func(ctx context.Context) error {
+				var mux *http.ServeMux
+				return c.Resolve(&mux)
+			},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		err = dispatch(ctx, &stdLogger{}, observer{}, cancel, []Dispatcher{dispatcher})
+		require.EqualError(t, err, "failure: *slice.DispatcherMock: panic: unexpected panic")
+	})
 }
 
 func TestLifecycle_after(t *testing.T) {
@@ -188,7 +393,7 @@ func TestLifecycle_after(t *testing.T) {
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
-		err = beforeShutdown(ctx, c, hooks)
+		err = beforeShutdown(ctx, c, observer{}, hooks)
 		require.NoError(t, err)
 		require.Equal(t, []string{"third-shutdown", "second-shutdown", "first-shutdown"}, order)
 	})
@@ -219,7 +424,7 @@ func TestLifecycle_after(t *testing.T) {
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
-		err = beforeShutdown(ctx, c, hooks)
+		err = beforeShutdown(ctx, c, observer{}, hooks)
 		require.EqualError(t, err, "shutdown failed: shutdown third-shutdown failed: third-error; shutdown second-shutdown failed: second-error; shutdown first-shutdown failed: first-error")
 	})
 
@@ -250,7 +455,88 @@ func TestLifecycle_after(t *testing.T) {
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
 		defer cancel()
-		err = beforeShutdown(ctx, c, shutdowns)
+		err = beforeShutdown(ctx, c, observer{}, shutdowns)
 		require.EqualError(t, err, "shutdown failed: context deadline exceeded")
 	})
 }
+
+type traceDispatcher struct {
+	running chan struct{}
+}
+
+func (d *traceDispatcher) Run(ctx context.Context) error {
+	close(d.running)
+	<-ctx.Done()
+	return nil
+}
+
+func connectDatabase() {}
+func closeDatabase()   {}
+func connectCache()    {}
+func closeCache()      {}
+
+func TestLifecycle_trace(t *testing.T) {
+	database := Bundle{
+		Name:  "database",
+		Hooks: []Hook{{BeforeStart: connectDatabase, BeforeShutdown: closeDatabase}},
+	}
+	cache := Bundle{
+		Name:    "cache",
+		Bundles: []Bundle{database},
+		Hooks:   []Hook{{BeforeStart: connectCache, BeforeShutdown: closeCache}},
+	}
+	dispatcher := &traceDispatcher{running: make(chan struct{})}
+	trace := &testcmp.Trace{}
+	app := New(
+		WithName("app"),
+		WithArgs(),
+		WithEnvLookup(func(key string) (string, bool) { return "", false }),
+		WithLogger(&testcmp.Log{}),
+		WithTracer(trace),
+		WithBundles(cache),
+		WithComponents(Supply(dispatcher, di.As(new(Dispatcher)))),
+	)
+	done := make(chan error)
+	go func() {
+		done <- app.Start()
+	}()
+	<-dispatcher.running
+	app.Stop()
+	require.NoError(t, <-done)
+	testcmp.AssertGolden(t, "lifecycle", trace.String())
+}
+
+func TestApplication_Stop(t *testing.T) {
+	t.Run("stop before start does nothing", func(t *testing.T) {
+		app := New(WithName("app"))
+		require.NotPanics(t, app.Stop)
+	})
+}
+
+func TestLifecycle_catchSignals(t *testing.T) {
+	t.Run("signal handling stopped with application context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		app := &Application{Logger: &testcmp.Log{}, tracer: nopTracer{}, stop: cancel}
+		done := make(chan struct{})
+		go func() {
+			app.catchSignals(ctx)
+			close(done)
+		}()
+		cancel()
+		<-done
+	})
+}
+
+// delayClock records delays and fires immediately.
+type delayClock struct {
+	realClock
+	delays []time.Duration
+}
+
+// After implements Clock interface.
+func (c *delayClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
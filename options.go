@@ -32,6 +32,29 @@ func WithBundles(bundles ...Bundle) Option {
 	})
 }
 
+// WithRegisteredBundles enables bundles registered with slice.Register() by name. Bundles can be
+// enabled with SLICE_BUNDLES environment variable too, e.g. SLICE_BUNDLES=http,metrics.
+func WithRegisteredBundles(names ...string) Option {
+	return option(func(s *Application) {
+		s.registered = append(s.registered, names...)
+	})
+}
+
+// WithPlugins loads bundles from Go plugins (*.so files) found in the directory. Every plugin must
+// export Bundle variable of slice.Bundle type. Plugins are supported only on linux.
+func WithPlugins(dir string) Option {
+	return option(func(s *Application) {
+		s.plugins = dir
+	})
+}
+
+// WithChecks adds application preflight checks.
+func WithChecks(checks ...Check) Option {
+	return option(func(s *Application) {
+		s.Checks = append(s.Checks, checks...)
+	})
+}
+
 // WithComponents contains component options.
 func WithComponents(components ...ComponentOption) Option {
 	return option(func(s *Application) {
@@ -41,6 +64,15 @@ func WithComponents(components ...ComponentOption) Option {
 	})
 }
 
+// InferBundleOrder enables inferring of bundle order from components. If components, hooks or checks
+// of a bundle depend on a type provided by another bundle, the bundle boots after the provider.
+// Cycles between bundles found this way cause error.
+func InferBundleOrder() Option {
+	return option(func(s *Application) {
+		s.inferOrder = true
+	})
+}
+
 // WithParameterParser sets parser for application.
 func WithParameterParser(parser ParameterParser) Option {
 	return option(func(s *Application) {
@@ -48,6 +80,46 @@ func WithParameterParser(parser ParameterParser) Option {
 	})
 }
 
+// WithArgs sets command line arguments of application without program name. By default, os.Args[1:]
+// are used.
+func WithArgs(args ...string) Option {
+	return option(func(s *Application) {
+		s.args = append([]string{}, args...)
+	})
+}
+
+// WithEnvLookup sets function that looks up environment variables of application: ENV, DEBUG,
+// SLICE_BUNDLES, SLICE_DISABLE_BUNDLES, variables of conditions and parameters parsed by the default
+// ParameterParser. Custom ParameterParser reads environment on its own. By default, os.LookupEnv is used.
+func WithEnvLookup(lookup func(key string) (string, bool)) Option {
+	return option(func(s *Application) {
+		s.lookupEnv = lookup
+	})
+}
+
+// WithoutSignals disables handling of SIGTERM and SIGINT signals. Application is stopped only with
+// Application.Stop(). Use it when application is started in-process, e.g. in tests.
+func WithoutSignals() Option {
+	return option(func(s *Application) {
+		s.noSignals = true
+	})
+}
+
+// WithTracer sets tracer of application lifecycle steps.
+func WithTracer(tracer Tracer) Option {
+	return option(func(s *Application) {
+		s.tracer = tracer
+	})
+}
+
+// WithClock sets application clock. Clock is used for start and shutdown timeouts, retry backoff and
+// provided to the container. By default, time package is used.
+func WithClock(clock Clock) Option {
+	return option(func(s *Application) {
+		s.clock = clock
+	})
+}
+
 // WithLogger sets application logger.
 func WithLogger(logger Logger) Option {
 	return option(func(s *Application) {
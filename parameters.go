@@ -4,7 +4,10 @@ import (
 	"os"
 	"text/tabwriter"
 
+	"github.com/goava/di"
 	"github.com/kelseyhightower/envconfig"
+
+	"github.com/goava/slice/internal/envparse"
 )
 
 // DefaultTableFormat constant to use to display usage in a tabular format
@@ -30,10 +33,19 @@ type ParameterParser interface {
 }
 
 type stdParameterParser struct {
+	// lookup looks up environment variables, see slice.WithEnvLookup(). Process environment is used
+	// if it is nil.
+	lookup func(key string) (string, bool)
 }
 
 func (d stdParameterParser) Parse(prefix string, parameters ...Parameter) error {
 	for _, parameter := range parameters {
+		if d.lookup != nil {
+			if err := envparse.Process(d.lookup, prefix, parameter); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := envconfig.Process(prefix, parameter); err != nil {
 			return err
 		}
@@ -53,3 +65,28 @@ func (d stdParameterParser) Usage(prefix string, parameters ...Parameter) error
 	}
 	return tabs.Flush()
 }
+
+// parameterSet is a group of parameters with the same prefix.
+type parameterSet struct {
+	prefix     string
+	tags       di.Tags
+	parameters []Parameter
+}
+
+// collectParameters groups application and bundle parameters by prefix. Parameters of bundle instance
+// have their own prefix, see slice.Instance().
+func collectParameters(prefix string, parameters []Parameter, bundles []Bundle) []parameterSet {
+	sets := []parameterSet{{prefix: prefix, parameters: parameters}}
+	for _, bundle := range bundles {
+		if bundle.instance == "" {
+			sets[0].parameters = append(sets[0].parameters, bundle.Parameters...)
+			continue
+		}
+		sets = append(sets, parameterSet{
+			prefix:     instancePrefix(prefix, bundle.instance),
+			tags:       di.Tags{"name": bundle.instance},
+			parameters: bundle.Parameters,
+		})
+	}
+	return sets
+}
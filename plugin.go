@@ -0,0 +1,34 @@
+package slice
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// loadPlugins loads bundles from Go plugins (*.so files) found in the directory. Every plugin must
+// export Bundle variable of slice.Bundle type:
+//
+//	package main
+//
+//	var Bundle = bundle.New(
+//		bundle.WithName("integration"),
+//	)
+//
+// Plugins are loaded in lexical order of file names.
+func loadPlugins(dir string) ([]Bundle, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return nil, fmt.Errorf("plugins: %w", err)
+	}
+	sort.Strings(paths)
+	var bundles []Bundle
+	for _, path := range paths {
+		bundle, err := openPlugin(path)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", path, err)
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
@@ -0,0 +1,26 @@
+//go:build linux
+// +build linux
+
+package slice
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// openPlugin opens Go plugin and looks up exported Bundle variable.
+func openPlugin(path string) (Bundle, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return Bundle{}, err
+	}
+	symbol, err := p.Lookup("Bundle")
+	if err != nil {
+		return Bundle{}, err
+	}
+	bundle, ok := symbol.(*Bundle)
+	if !ok {
+		return Bundle{}, fmt.Errorf("Bundle symbol must be slice.Bundle, got %T", symbol)
+	}
+	return *bundle, nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package slice
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// openPlugin returns error, Go plugins are supported only on linux.
+func openPlugin(path string) (Bundle, error) {
+	return Bundle{}, fmt.Errorf("plugins are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,30 @@
+package slice
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("empty directory", func(t *testing.T) {
+		bundles, err := loadPlugins(dir)
+		require.NoError(t, err)
+		require.Len(t, bundles, 0)
+	})
+
+	t.Run("invalid plugin causes error", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.so")
+		require.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0600))
+		_, err := loadPlugins(dir)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "plugin "+path+": ")
+	})
+}
@@ -0,0 +1,78 @@
+package slice
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/goava/di"
+)
+
+// Check is a preflight check. Checks run after configuring and before any BeforeStart hook.
+//
+//	slice.Check{
+//		Name: "data dir writable",
+//		Run: func(params *Parameters) error {
+//			return unix.Access(params.DataDir, unix.W_OK)
+//		},
+//	}
+type Check struct {
+	// Name is a check name.
+	Name string
+	// Run invokes check. Like hooks, it may have dependencies and should return error on failure.
+	Run di.Invocation
+	// Warning marks check as non-critical. Its failure reported but does not abort boot.
+	Warning bool
+}
+
+type check struct {
+	bundle string
+	// container of bundle with private components
+	container *di.Container
+	Check
+}
+
+// preflight runs checks and prints results table to w. All critical failures returned at once.
+func preflight(w io.Writer, container *di.Container, checks []check) error {
+	tabs := tabwriter.NewWriter(w, 1, 0, 4, ' ', 0)
+	if _, err := fmt.Fprint(tabs, "BUNDLE\tCHECK\tSTATUS\tMESSAGE\n"); err != nil {
+		return err
+	}
+	var errs preflightErrors
+	for _, c := range checks {
+		status, message := "pass", ""
+		scope := container
+		if c.container != nil {
+			scope = c.container
+		}
+		if err := scope.Invoke(c.Run); err != nil {
+			status, message = "fail", err.Error()
+			if c.Warning {
+				status = "warn"
+			} else {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", c.bundle, c.Name, err))
+			}
+		}
+		if _, err := fmt.Fprintf(tabs, "%s\t%s\t%s\t%s\n", c.bundle, c.Name, status, message); err != nil {
+			return err
+		}
+	}
+	if err := tabs.Flush(); err != nil {
+		return err
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+type preflightErrors []error
+
+// Error implements error interface.
+func (e preflightErrors) Error() (r string) {
+	r = "preflight checks failed:\n"
+	for _, err := range e {
+		r = fmt.Sprintf("%s- %s\n", r, err)
+	}
+	return r
+}
@@ -0,0 +1,47 @@
+package slice
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflight(t *testing.T) {
+	t.Run("all checks passed", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		var out bytes.Buffer
+		err = preflight(&out, c, []check{
+			{bundle: "app", Check: Check{Name: "first", Run: func() error { return nil }}},
+			{bundle: "db", Check: Check{Name: "second", Run: func() {}}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "BUNDLE    CHECK     STATUS    MESSAGE\napp       first     pass      \ndb        second    pass      \n", out.String())
+	})
+
+	t.Run("warnings do not abort boot", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		var out bytes.Buffer
+		err = preflight(&out, c, []check{
+			{bundle: "app", Check: Check{Name: "clock", Run: func() error { return errors.New("skewed") }, Warning: true}},
+		})
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "app       clock    warn      skewed")
+	})
+
+	t.Run("all failures listed", func(t *testing.T) {
+		c, err := di.New()
+		require.NoError(t, err)
+		var out bytes.Buffer
+		err = preflight(&out, c, []check{
+			{bundle: "app", Check: Check{Name: "disk", Run: func() error { return errors.New("no space") }}},
+			{bundle: "app", Check: Check{Name: "clock", Run: func() error { return nil }}},
+			{bundle: "db", Check: Check{Name: "dir", Run: func() error { return errors.New("not writable") }}},
+		})
+		require.EqualError(t, err, "preflight checks failed:\n- app: disk: no space\n- db: dir: not writable\n")
+	})
+}
@@ -0,0 +1,155 @@
+package slice
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/goava/di"
+)
+
+// Private registers bundle components that are visible only to the bundle. Private components can be
+// resolved by constructors, hooks and checks of the bundle. Other components of the bundle are
+// exported to the application.
+//
+//	bundle.WithComponents(
+//		slice.Private(
+//			slice.Provide(NewConnectionPool),
+//		),
+//		slice.Provide(NewRepository), // NewRepository depends on the pool
+//	)
+func Private(options ...ComponentOption) ComponentOption {
+	return option(func(s *Application) {
+		private := s.private
+		s.private = true
+		for _, o := range options {
+			o.apply(s)
+		}
+		s.private = private
+	})
+}
+
+// createScopes creates containers of bundles with private components. Bundle container has the application
+// container as a parent. Exported components of bundle are provided to the application container,
+// their private dependencies are resolved from the bundle container.
+func createScopes(defaults []di.Option, components []component) (*di.Container, map[string]*di.Container, error) {
+	bundlePrivates := map[string][]component{}
+	var privates []component
+	for _, c := range components {
+		if !c.private {
+			continue
+		}
+		if c.bundle == "" {
+			return nil, nil, fmt.Errorf("private components could be registered only by bundle")
+		}
+		bundlePrivates[c.bundle] = append(bundlePrivates[c.bundle], c)
+		privates = append(privates, c)
+	}
+	scopes := map[string]*di.Container{}
+	options := defaults
+	for _, c := range components {
+		if c.private {
+			continue
+		}
+		if private, ok := bundlePrivates[c.bundle]; ok && !c.supplied {
+			bundle := c.bundle
+			c.constructor = rewire(c.constructor, providedTypes(private, c.dependencies()), func(ptr di.Pointer) error {
+				return scopes[bundle].Resolve(ptr)
+			})
+		}
+		options = append(options, c.option())
+	}
+	container, err := createContainer(options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range privates {
+		if _, ok := scopes[c.bundle]; ok {
+			continue
+		}
+		var options []di.Option
+		for _, p := range privates {
+			if p.bundle == c.bundle {
+				options = append(options, p.option())
+			}
+		}
+		scope, err := createContainer(options...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", c.bundle, err)
+		}
+		if err := scope.AddParent(container); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", c.bundle, err)
+		}
+		scopes[c.bundle] = scope
+	}
+	return container, scopes, nil
+}
+
+// providedTypes returns types from candidates that are provided by components.
+func providedTypes(components []component, candidates []reflect.Type) map[reflect.Type]bool {
+	types := map[reflect.Type]bool{}
+	for _, t := range candidates {
+		for _, c := range components {
+			if c.provides(t) {
+				types[t] = true
+			}
+		}
+	}
+	return types
+}
+
+var errorType = reflect.TypeOf(new(error)).Elem()
+
+// rewire creates function that resolves dependencies of specified types with resolve function. Other
+// dependencies stay function parameters. Error result will be added to function if needed.
+func rewire(fn interface{}, types map[reflect.Type]bool, resolve func(ptr di.Pointer) error) interface{} {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func {
+		// invalid signature error will be returned by container
+		return fn
+	}
+	var in, out []reflect.Type
+	var rewired bool
+	for i := 0; i < ft.NumIn(); i++ {
+		if types[ft.In(i)] {
+			rewired = true
+			continue
+		}
+		in = append(in, ft.In(i))
+	}
+	if !rewired {
+		return fn
+	}
+	for i := 0; i < ft.NumOut(); i++ {
+		out = append(out, ft.Out(i))
+	}
+	hasError := ft.NumOut() > 0 && ft.Out(ft.NumOut()-1) == errorType
+	if !hasError {
+		out = append(out, errorType)
+	}
+	return reflect.MakeFunc(reflect.FuncOf(in, out, false), func(args []reflect.Value) []reflect.Value {
+		var values []reflect.Value
+		for i := 0; i < ft.NumIn(); i++ {
+			if !types[ft.In(i)] {
+				values = append(values, args[0])
+				args = args[1:]
+				continue
+			}
+			v := reflect.New(ft.In(i))
+			if err := resolve(v.Interface()); err != nil {
+				results := make([]reflect.Value, len(out))
+				for j := range out {
+					results[j] = reflect.Zero(out[j])
+				}
+				results[len(out)-1] = reflect.ValueOf(&err).Elem()
+				return results
+			}
+			values = append(values, v.Elem())
+		}
+		results := fv.Call(values)
+		if !hasError {
+			results = append(results, reflect.Zero(errorType))
+		}
+		return results
+	}).Interface()
+}
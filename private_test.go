@@ -0,0 +1,83 @@
+package slice
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+)
+
+type pool struct {
+	bundle string
+}
+
+type repository struct {
+	pool *pool
+}
+
+func TestCreateScopes(t *testing.T) {
+	users := Bundle{
+		Name: "users",
+		Components: []ComponentOption{
+			Private(
+				Provide(func() *pool { return &pool{bundle: "users"} }),
+			),
+			Provide(func(p *pool, mux *http.ServeMux) *repository { return &repository{pool: p} }),
+		},
+	}
+	orders := Bundle{
+		Name: "orders",
+		Components: []ComponentOption{
+			Private(
+				Supply(&pool{bundle: "orders"}),
+			),
+			Provide(http.NewServeMux),
+		},
+	}
+
+	t.Run("private components resolved only by bundle", func(t *testing.T) {
+		container, scopes, err := createScopes(nil, bundleComponents(users, orders))
+		require.NoError(t, err)
+		require.Len(t, scopes, 2)
+		var repo *repository
+		require.NoError(t, container.Resolve(&repo))
+		require.Equal(t, "users", repo.pool.bundle)
+		var p *pool
+		has, err := container.Has(&p)
+		require.NoError(t, err)
+		require.False(t, has)
+		require.NoError(t, scopes["orders"].Resolve(&p))
+		require.Equal(t, "orders", p.bundle)
+		require.NoError(t, scopes["users"].Invoke(func(p *pool, repo *repository) {
+			require.Equal(t, "users", p.bundle)
+			require.Same(t, p, repo.pool)
+		}))
+	})
+
+	t.Run("private dependency error returned by exported constructor", func(t *testing.T) {
+		broken := Bundle{
+			Name: "broken",
+			Components: []ComponentOption{
+				Private(
+					Provide(func() (*pool, error) { return nil, errors.New("connection refused") }),
+				),
+				Provide(func(p *pool) *repository { return &repository{pool: p} }),
+			},
+		}
+		container, _, err := createScopes(nil, bundleComponents(broken))
+		require.NoError(t, err)
+		var repo *repository
+		err = container.Resolve(&repo)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection refused")
+	})
+
+	t.Run("private application components cause error", func(t *testing.T) {
+		app := &Application{}
+		Private(Supply(&pool{})).apply(app)
+		_, _, err := createScopes([]di.Option{}, app.components)
+		require.EqualError(t, err, "private components could be registered only by bundle")
+	})
+}
@@ -0,0 +1,83 @@
+package slice
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultBundles is an environment variable with comma-separated names of registered bundles.
+const defaultBundles = "SLICE_BUNDLES"
+
+// registry contains bundles registered with slice.Register().
+var registry = struct {
+	sync.RWMutex
+	bundles map[string]Bundle
+}{bundles: map[string]Bundle{}}
+
+// Register makes bundle available by name. It is intended to be called from init() function of bundle
+// package, like database/sql drivers:
+//
+//	func init() {
+//		slice.Register(Bundle)
+//	}
+//
+// Registered bundles are not started until enabled with slice.WithRegisteredBundles() or
+// SLICE_BUNDLES environment variable. Register panics if bundle name is empty or the name
+// is already registered.
+func Register(bundle Bundle) {
+	registry.Lock()
+	defer registry.Unlock()
+	if bundle.Name == "" {
+		panic("slice: Register bundle with empty name")
+	}
+	if _, dup := registry.bundles[bundle.Name]; dup {
+		panic("slice: Register called twice for bundle " + bundle.Name)
+	}
+	registry.bundles[bundle.Name] = bundle
+}
+
+// Registered returns a sorted list of registered bundle names.
+func Registered() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registeredNames()
+}
+
+// registeredBundles returns registered bundles enabled by names and SLICE_BUNDLES environment variable.
+func registeredBundles(info Info, names []string) ([]Bundle, error) {
+	if env, ok := info.lookupEnv(defaultBundles); ok {
+		for _, name := range strings.Split(env, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	var bundles []Bundle
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		bundle, ok := registry.bundles[name]
+		if !ok {
+			return nil, fmt.Errorf("bundle %s not registered, registered bundles: %s", name, strings.Join(registeredNames(), ", "))
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
+
+// registeredNames returns sorted names of registered bundles. Registry must be locked.
+func registeredNames() []string {
+	var names []string
+	for name := range registry.bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
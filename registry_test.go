@@ -0,0 +1,54 @@
+package slice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	defer func(bundles map[string]Bundle, lookup func(string) (string, bool)) {
+		registry.bundles = bundles
+		lookupEnv = lookup
+	}(registry.bundles, lookupEnv)
+	registry.bundles = map[string]Bundle{}
+	env := map[string]string{}
+	lookupEnv = func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	Register(Bundle{Name: "metrics"})
+	Register(Bundle{Name: "http"})
+	Register(Bundle{Name: "grpc"})
+
+	t.Run("registered bundles sorted by name", func(t *testing.T) {
+		require.Equal(t, []string{"grpc", "http", "metrics"}, Registered())
+	})
+
+	t.Run("duplicate or empty name causes panic", func(t *testing.T) {
+		require.PanicsWithValue(t, "slice: Register called twice for bundle http", func() {
+			Register(Bundle{Name: "http"})
+		})
+		require.PanicsWithValue(t, "slice: Register bundle with empty name", func() {
+			Register(Bundle{})
+		})
+	})
+
+	t.Run("bundles enabled by option and environment", func(t *testing.T) {
+		env[defaultBundles] = " metrics, http ,"
+		defer delete(env, defaultBundles)
+		bundles, err := registeredBundles(Info{}, []string{"http", "grpc"})
+		require.NoError(t, err)
+		var names []string
+		for _, b := range bundles {
+			names = append(names, b.Name)
+		}
+		require.Equal(t, []string{"http", "grpc", "metrics"}, names)
+	})
+
+	t.Run("unknown bundle causes error", func(t *testing.T) {
+		_, err := registeredBundles(Info{}, []string{"kafka"})
+		require.EqualError(t, err, "bundle kafka not registered, registered bundles: grpc, http, metrics")
+	})
+}
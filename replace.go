@@ -0,0 +1,78 @@
+package slice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Replace substitutes component provided by application or any bundle, including nested ones. The type
+// of replaced component is the first result of constructor:
+//
+//	slice.When(slice.InEnv("dev"),
+//		slice.Replace(func() mail.Sender { return &FakeSender{} }),
+//	)
+//
+// Replacement keeps provide options and visibility of the original component. The original constructor
+// is not called. Decorators are applied to replacement. Private components are replaced only by their bundle.
+func Replace(constructor interface{}) ComponentOption {
+	return option(func(s *Application) {
+		s.replacements = append(s.replacements, replacement{
+			bundle:      s.bundle,
+			constructor: constructor,
+		})
+	})
+}
+
+type replacement struct {
+	bundle      string
+	constructor interface{}
+}
+
+// errorf returns error of replacement prefixed with name of bundle that registered it.
+func (r replacement) errorf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if r.bundle == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", r.bundle, err)
+}
+
+// replace substitutes components with replacements in registration order.
+func replace(components []component, replacements []replacement) ([]component, error) {
+	if len(replacements) == 0 {
+		return components, nil
+	}
+	components = append([]component(nil), components...)
+	for _, r := range replacements {
+		ct := reflect.TypeOf(r.constructor)
+		if ct == nil || ct.Kind() != reflect.Func || ct.NumOut() == 0 || ct.NumOut() > 2 ||
+			ct.NumOut() == 2 && ct.Out(1) != errorType {
+			return nil, r.errorf("replace: invalid constructor signature, got %s", ct)
+		}
+		rt := ct.Out(0)
+		found := visibleProviders(components, rt, r.bundle)
+		if len(found) == 0 {
+			return nil, r.errorf("replace %s: type not provided", rt)
+		}
+		if len(found) > 1 {
+			return nil, r.errorf("replace %s: multiple definitions", rt)
+		}
+		c := components[found[0]]
+		if c.result() == rt {
+			c.constructor = r.constructor
+			c.supplied = false
+			c.value = nil
+			components[found[0]] = c
+			continue
+		}
+		// type provided as interface: the replacement will be provided by separate component
+		c.options = c.withoutInterface(rt)
+		components[found[0]] = c
+		components = append(components, component{
+			bundle:      c.bundle,
+			constructor: r.constructor,
+			private:     c.private,
+		})
+	}
+	return components, nil
+}
@@ -0,0 +1,136 @@
+package slice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goava/slice/testcmp"
+)
+
+func TestReplace(t *testing.T) {
+	provider := Bundle{
+		Name: "provider",
+		Components: []ComponentOption{
+			Provide(func() (greeting, error) { return "", errors.New("should not be called") }, di.As(new(greeter))),
+		},
+	}
+
+	t.Run("constructor replaced with provide options", func(t *testing.T) {
+		c, err := buildContainer([]Bundle{provider}, Replace(func() greeting { return "replaced" }))
+		require.NoError(t, err)
+		var g greeting
+		require.NoError(t, c.Resolve(&g))
+		require.Equal(t, greeting("replaced"), g)
+		var i greeter
+		require.NoError(t, c.Resolve(&i))
+		require.Equal(t, "replaced", i.Greet())
+	})
+
+	t.Run("interface replaced and decorated", func(t *testing.T) {
+		c, err := buildContainer([]Bundle{provider},
+			Replace(func() greeter { return greeting("fake") }),
+			Decorate(func(g greeter) greeter { return greeting(g.Greet() + " decorated") }),
+		)
+		require.NoError(t, err)
+		var g greeter
+		require.NoError(t, c.Resolve(&g))
+		require.Equal(t, "fake decorated", g.Greet())
+	})
+
+	t.Run("type not provided", func(t *testing.T) {
+		_, err := buildContainer(nil, Replace(func() greeting { return "" }))
+		require.EqualError(t, err, "replace slice.greeting: type not provided")
+	})
+
+	t.Run("private components of other bundles ignored", func(t *testing.T) {
+		pools := Bundle{
+			Name: "pools",
+			Components: []ComponentOption{
+				Private(Provide(func() greeting { return "private" })),
+			},
+		}
+		provider := Bundle{
+			Name: "provider",
+			Components: []ComponentOption{
+				Provide(func() greeting { return "exported" }),
+			},
+		}
+		c, err := buildContainer([]Bundle{pools, provider}, Replace(func() greeting { return "replaced" }))
+		require.NoError(t, err)
+		var g greeting
+		require.NoError(t, c.Resolve(&g))
+		require.Equal(t, greeting("replaced"), g)
+		_, err = buildContainer([]Bundle{pools}, Replace(func() greeting { return "replaced" }))
+		require.EqualError(t, err, "replace slice.greeting: type not provided")
+	})
+
+	t.Run("private component replaced by its bundle", func(t *testing.T) {
+		pools := Bundle{
+			Name: "pools",
+			Components: []ComponentOption{
+				Private(Provide(func() greeting { return "private" })),
+				Replace(func() greeting { return "replaced" }),
+			},
+		}
+		_, scopes, err := buildScopes([]Bundle{pools})
+		require.NoError(t, err)
+		var g greeting
+		require.NoError(t, scopes["pools"].Resolve(&g))
+		require.Equal(t, greeting("replaced"), g)
+	})
+
+	t.Run("bundle of replacement reported", func(t *testing.T) {
+		consumer := Bundle{
+			Name:       "consumer",
+			Components: []ComponentOption{Replace(func() greeting { return "" })},
+		}
+		_, err := buildContainer([]Bundle{consumer})
+		require.EqualError(t, err, "consumer: replace slice.greeting: type not provided")
+	})
+
+	t.Run("replacement dependencies infer bundle order", func(t *testing.T) {
+		type address string
+		var order []string
+		api := Bundle{
+			Name:       "api",
+			Components: []ComponentOption{Provide(func() greeting { return "api" })},
+			Hooks: []Hook{{
+				BeforeStart: func(g greeting) { order = append(order, "api") },
+			}},
+		}
+		config := Bundle{
+			Name:       "config",
+			Components: []ComponentOption{Supply(address("fake"))},
+			Hooks: []Hook{{
+				BeforeStart: func() { order = append(order, "config") },
+			}},
+		}
+		dispatcher := func() Dispatcher {
+			return testcmp.FuncDispatcher{RunFunc: func(ctx context.Context) error { return nil }}
+		}
+		app := New(
+			WithName("app"),
+			WithArgs(),
+			WithoutSignals(),
+			WithEnvLookup(func(key string) (string, bool) { return "", false }),
+			WithLogger(&testcmp.FmtLog{}),
+			InferBundleOrder(),
+			WithBundles(api, config),
+			WithComponents(
+				Replace(func(a address) greeting { return greeting(a) }),
+				Provide(dispatcher),
+			),
+		)
+		require.NoError(t, app.Start())
+		require.Equal(t, []string{"config", "api"}, order)
+	})
+
+	t.Run("invalid constructor signature", func(t *testing.T) {
+		_, err := buildContainer(nil, Replace(func() {}))
+		require.EqualError(t, err, "replace: invalid constructor signature, got func()")
+	})
+}
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/goava/di"
+
+	"github.com/goava/slice/internal/intercept"
 )
 
 const (
@@ -56,13 +58,44 @@ type Application struct {
 	Parameters      []Parameter
 	Dispatcher      Dispatcher
 	Bundles         []Bundle
+	Checks          []Check
 	StartTimeout    time.Duration
 	ShutdownTimeout time.Duration
 	Logger          Logger
 	ParameterParser ParameterParser
 
-	// providers contains type providers. Only slice.Provide() and slice.Supply() works.
-	providers []di.Option
+	// components contains registered components. Only slice.Provide() and slice.Supply() works.
+	components []component
+	// bundle is a name of the bundle whose components are applying
+	bundle string
+	// private is true while private components are applying
+	private bool
+	// decorators contains component decorators, see slice.Decorate().
+	decorators []decorator
+	// replacements contains component replacements, see slice.Replace().
+	replacements []replacement
+	// registered contains names of enabled registered bundles, see slice.WithRegisteredBundles().
+	registered []string
+	// plugins is a directory with bundle plugins, see slice.WithPlugins().
+	plugins string
+	// inferOrder enables inferring of bundle order from components, see slice.InferBundleOrder().
+	inferOrder bool
+	// conditionals contains options that will be applied on start, see slice.When().
+	conditionals []conditional
+	// info is set on start when conditions can be evaluated
+	info *Info
+	// args contains command line arguments, see slice.WithArgs().
+	args []string
+	// tracer observes lifecycle steps, see slice.WithTracer().
+	tracer Tracer
+	// clock provides time of lifecycle, see slice.WithClock().
+	clock Clock
+	// interceptors are called before lifecycle steps, see slicetest.WithInterceptor().
+	interceptors []intercept.Interceptor
+	// lookupEnv looks up environment variables, see slice.WithEnvLookup().
+	lookupEnv func(key string) (string, bool)
+	// noSignals disables handling of os signals, see slice.WithoutSignals().
+	noSignals bool
 	env       Env
 	debug     bool
 	state     state
@@ -71,33 +104,94 @@ type Application struct {
 
 // Start starts application.
 func (app *Application) Start() error {
-	initStart := time.Now()
+	if app.clock == nil {
+		app.clock = realClock{}
+	}
+	initStart := app.clock.Now()
 	// STATE: INITIALIZATION
 	app.state = initialization
 	if app.Logger == nil {
 		app.Logger = &stdLogger{} // std logger logs messages before container initialization
 	}
+	if app.tracer == nil {
+		app.tracer = nopTracer{}
+	}
+	obs := observer{tracer: app.tracer, interceptors: app.interceptors}
 	if len(app.Name) == 0 {
 		return fmt.Errorf("application name must be specified, see slice.SetName() option")
 	}
 	// initialize context
 	base, stop := context.WithCancel(context.Background())
+	defer stop()
 	app.stop = stop
 	ctx := NewContext(base)
 	// lookup environment
-	env, _ := lookupEnv(defaultEnv)
+	info := Info{lookup: app.lookupEnv}
+	env, _ := info.lookupEnv(defaultEnv)
 	app.env = parseEnv(env)
-	if debug, ok := lookupEnv(defaultDebug); ok {
+	if debug, ok := info.lookupEnv(defaultDebug); ok {
 		app.debug = strings.ToLower(debug) == "true"
 	}
 	// build app info
-	info := Info{
-		Name:  app.Name,
-		Env:   app.env,
-		Debug: app.debug,
+	info.Name = app.Name
+	info.Env = app.env
+	info.Debug = app.debug
+	// parameters of conditions are parsed before container initialization
+	var conditionErr error
+	info.parse = func(parameter Parameter) error {
+		parser := app.ParameterParser
+		if parser == nil {
+			parser = &stdParameterParser{lookup: app.lookupEnv}
+		}
+		err := parser.Parse(app.Prefix, parameter)
+		if err != nil && conditionErr == nil {
+			conditionErr = err
+		}
+		return err
+	}
+	health := &Health{}
+	// create application flag set
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	// check parameters
+	var parametersFlag bool
+	fs.BoolVar(&parametersFlag, "parameters", false, "Display parameters information")
+	var preflightFlag bool
+	fs.BoolVar(&preflightFlag, "preflight", false, "Run preflight checks and exit")
+	var bundlesFlag bool
+	fs.BoolVar(&bundlesFlag, "bundles", false, "Display bundles information")
+	var disabled names
+	fs.Var(&disabled, "disable-bundle", "Disable bundle by name, could be repeated")
+	if app.args == nil {
+		app.args = os.Args[1:]
+	}
+	// Ignore errors; CommandLine is set for ExitOnError.
+	_ = fs.Parse(app.args)
+	if env, ok := info.lookupEnv(defaultDisableBundles); ok {
+		_ = disabled.Set(env)
+	}
+	// apply application conditional options
+	app.applyConditionals(info)
+	// enable registered bundles
+	registered, err := registeredBundles(info, app.registered)
+	if err != nil {
+		return fmt.Errorf("registered bundles: %w", err)
+	}
+	roots := append(append([]Bundle(nil), app.Bundles...), registered...)
+	// load bundles from plugins
+	if app.plugins != "" {
+		plugins, err := loadPlugins(app.plugins)
+		if err != nil {
+			return fmt.Errorf("load plugins: %w", err)
+		}
+		roots = append(roots, plugins...)
 	}
 	// check bundle acyclic and sort dependencies
-	sorted, err := prepareBundles(app.Bundles)
+	sorted, err := prepareBundles(roots, info, nil)
+	if err != nil {
+		return fmt.Errorf("prepare bundles: %w", err)
+	}
+	// remove disabled bundles
+	sorted, err = disableBundles(app.Logger, sorted, disabled)
 	if err != nil {
 		return fmt.Errorf("prepare bundles: %w", err)
 	}
@@ -105,18 +199,51 @@ func (app *Application) Start() error {
 	for _, bundle := range sorted {
 		bundle.apply(app)
 	}
+	if conditionErr != nil {
+		return fmt.Errorf("conditions: parameters: %w", conditionErr)
+	}
+	// replace components
+	components, err := replace(app.components, app.replacements)
+	if err != nil {
+		return fmt.Errorf("initialization: %w", err)
+	}
+	// sort bundles again with respect of component dependencies
+	if app.inferOrder {
+		sorted, err = prepareBundles(roots, info, inferBundleOrder(sorted, components))
+		if err != nil {
+			return fmt.Errorf("infer bundle order: %w", err)
+		}
+		sorted, _ = disableBundles(nil, sorted, disabled)
+	}
 	// prepare application components
 	providers := []di.Option{
 		di.Provide(func() *Context { return ctx }, di.As(new(context.Context))),
 		di.Provide(func() Env { return app.env }),
 		di.Provide(func() Info { return info }),
+		di.Provide(func() *Health { return health }),
+		di.Provide(func() Clock { return app.clock }),
+	}
+	// decorate components, decorated logger is resolved on configuring
+	components = decorateLogger(components, app.decorators, app.Logger)
+	components, err = decorate(components, app.decorators, sorted, app.Parameters)
+	if err != nil {
+		return fmt.Errorf("initialization: %w", err)
+	}
+	// tag components of bundle instances
+	var container *di.Container
+	components, sorted, err = instantiate(components, sorted, func() *di.Container { return container })
+	if err != nil {
+		return fmt.Errorf("initialization: %w", err)
 	}
-	providers = append(providers, app.providers...)
 	// validate container with all application components
-	container, err := createContainer(providers...)
+	container, scopes, err := createScopes(providers, components)
 	if err != nil {
 		return fmt.Errorf("initialization: %w", err)
 	}
+	// bundles with private components use their own containers
+	for i := range sorted {
+		sorted[i].container = scopes[sorted[i].Name]
+	}
 	// STATE: CONFIGURING
 	app.state = configuring
 	if app.ParameterParser == nil {
@@ -125,38 +252,41 @@ func (app *Application) Start() error {
 			return fmt.Errorf("configuring: parameter parser: %w", err)
 		}
 		if err != nil && errors.Is(err, di.ErrTypeNotExists) {
-			app.ParameterParser = &stdParameterParser{}
+			app.ParameterParser = &stdParameterParser{lookup: app.lookupEnv}
 		}
 	}
-	// collect application parameters
-	parameters := app.Parameters
-	// add bundle parameters
-	for _, bundle := range sorted {
-		parameters = append(parameters, bundle.Parameters...)
-	}
-	// create application flag set
-	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
-	// check parameters
-	var parametersFlag bool
-	fs.BoolVar(&parametersFlag, "parameters", false, "Display parameters information")
-	// Ignore errors; CommandLine is set for ExitOnError.
-	_ = fs.Parse(os.Args[1:])
-	if parametersFlag {
-		if err := app.ParameterParser.Usage(app.Prefix, parameters...); err != nil {
-			return fmt.Errorf("configuring: usage: %w", err)
+	// collect application and bundle parameters
+	parameters := collectParameters(app.Prefix, app.Parameters, sorted)
+	if bundlesFlag {
+		if err := printBundles(os.Stdout, sorted, components); err != nil {
+			return fmt.Errorf("configuring: bundles: %w", err)
 		}
 		return nil
 	}
-	// parameter parser decorator, implemented for lazy parameter loading
-	parseParameters := func(pointer di.Value) error {
-		if err := app.ParameterParser.Parse(app.Prefix, pointer); err != nil {
-			return fmt.Errorf("configuring: parse: %w", err)
+	if parametersFlag {
+		for _, set := range parameters {
+			if len(set.parameters) == 0 {
+				continue
+			}
+			if err := app.ParameterParser.Usage(set.prefix, set.parameters...); err != nil {
+				return fmt.Errorf("configuring: usage: %w", err)
+			}
 		}
 		return nil
 	}
-	for _, parameter := range parameters {
-		if err := container.ProvideValue(parameter, di.Decorate(parseParameters)); err != nil {
-			return fmt.Errorf("configuring: parameters: %w", err)
+	for _, set := range parameters {
+		prefix := set.prefix
+		// parameter parser decorator, implemented for lazy parameter loading
+		parseParameters := func(pointer di.Value) error {
+			if err := app.ParameterParser.Parse(prefix, pointer); err != nil {
+				return fmt.Errorf("configuring: parse: %w", err)
+			}
+			return nil
+		}
+		for _, parameter := range set.parameters {
+			if err := container.ProvideValue(parameter, di.Decorate(parseParameters), set.tags); err != nil {
+				return fmt.Errorf("configuring: parameters: %w", err)
+			}
 		}
 	}
 	// resolve logger
@@ -169,8 +299,30 @@ func (app *Application) Start() error {
 	if err != nil && !errors.Is(err, di.ErrTypeNotExists) {
 		return fmt.Errorf("configuring: logger: %w", err)
 	}
+	// check bundle contracts
+	if err := checkContracts(container, sorted, components); err != nil {
+		return fmt.Errorf("configuring: %w", err)
+	}
 	app.Logger.Printf("slice", "Environment: %s", app.env)
 	app.Logger.Printf("slice", "Debug: %t", app.debug)
+	// run preflight checks
+	var checks []check
+	for _, c := range app.Checks {
+		checks = append(checks, check{bundle: app.Name, Check: c})
+	}
+	for _, bundle := range sorted {
+		for _, c := range bundle.Checks {
+			checks = append(checks, check{bundle: bundle.Name, container: bundle.container, Check: c})
+		}
+	}
+	if len(checks) != 0 || preflightFlag {
+		if err := preflight(os.Stdout, container, checks); err != nil {
+			return fmt.Errorf("configuring: %w", err)
+		}
+	}
+	if preflightFlag {
+		return nil
+	}
 	// STATE: STARTING
 	app.state = starting
 	var dispatchers []Dispatcher
@@ -189,58 +341,76 @@ func (app *Application) Start() error {
 		app.ShutdownTimeout = defaultTimeout
 	}
 	// start goroutine with os signal catch
-	go app.catchSignals()
-	startCtx, startCancel := context.WithTimeout(ctx, app.StartTimeout)
+	if !app.noSignals {
+		go app.catchSignals(ctx)
+	}
+	startCtx, startCancel := contextWithTimeout(ctx, app.clock, app.StartTimeout)
 	// boot bundles
-	hooks, err := beforeStart(startCtx, container, sorted...)
+	hooks, err := beforeStart(startCtx, container, app.Logger, app.clock, obs, health, sorted...)
 	startCancel()
 	// if boot failed shutdown booted bundles
 	if err != nil {
-		// create context for shutdown
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), app.ShutdownTimeout)
-		defer cancel()
-		if rserr := beforeShutdown(shutdownCtx, container, hooks); rserr != nil {
-			return fmt.Errorf("%w (%s)", err, rserr)
-		}
-		printStartError(err)
+		return app.rollback(container, obs, hooks, fmt.Errorf("starting: %w", err))
 	}
 	if !info.Env.IsTest() {
-		app.Logger.Printf("slice", "Initialization %s", time.Now().Sub(initStart))
+		app.Logger.Printf("slice", "Initialization %s", app.clock.Now().Sub(initStart))
 	}
 	app.Logger.Printf("slice", "Starting")
-	// resolve dispatchers
-	if err := container.Resolve(&dispatchers); err != nil {
-		return fmt.Errorf("dispatch failed: %w", err)
+	// resolve dispatchers, booted bundles are shut down if resolve failed
+	err = recovered(func() error {
+		return container.Resolve(&dispatchers)
+	})
+	if err != nil {
+		return app.rollback(container, obs, hooks, fmt.Errorf("dispatch failed: %w", err))
 	}
 	// STATE: RUNNING
 	app.state = running
 	// dispatch application, ignore context cancel error
 	// default context lifecycle used for application shutdown
-	if err := dispatch(ctx, app.Logger, stop, dispatchers); err != nil && !errors.Is(err, context.Canceled) {
+	if err := dispatch(ctx, app.Logger, obs, stop, dispatchers); err != nil && !errors.Is(err, context.Canceled) {
 		return err
 	}
 	// STATE: SHUTDOWN
 	app.state = shutdown
 	// create context for shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), app.ShutdownTimeout)
+	shutdownCtx, cancel := contextWithTimeout(context.Background(), app.clock, app.ShutdownTimeout)
 	defer cancel()
 	// shutdown bundles in reverse order
-	if err = beforeShutdown(shutdownCtx, container, hooks); err != nil {
+	if err = beforeShutdown(shutdownCtx, container, obs, hooks); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 	return err
 }
 
-// Stop stops application.
+// rollback shuts down booted bundles after start failure and returns the failure.
+func (app *Application) rollback(container *di.Container, obs observer, hooks []hook, err error) error {
+	shutdownCtx, cancel := contextWithTimeout(context.Background(), app.clock, app.ShutdownTimeout)
+	defer cancel()
+	if rserr := beforeShutdown(shutdownCtx, container, obs, hooks); rserr != nil {
+		return fmt.Errorf("%w (%s)", err, rserr)
+	}
+	return err
+}
+
+// Stop stops application. Stop does nothing if application is not started.
 func (app *Application) Stop() {
+	if app.stop == nil {
+		return
+	}
+	app.tracer.Trace(StepSignal, "slice", "stop")
 	app.stop()
 }
 
-// catchSignals waits SIGTERM or SIGINT signals
-func (app *Application) catchSignals() {
-	stop := make(chan os.Signal)
+// catchSignals waits SIGTERM or SIGINT signals until application context is done.
+func (app *Application) catchSignals(ctx context.Context) {
+	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
-	sign := <-stop
-	app.Logger.Printf("slice", strings.Title(sign.String()))
-	app.stop()
+	defer signal.Stop(stop)
+	select {
+	case sign := <-stop:
+		app.Logger.Printf("slice", strings.Title(sign.String()))
+		app.tracer.Trace(StepSignal, "slice", sign.String())
+		app.stop()
+	case <-ctx.Done():
+	}
 }
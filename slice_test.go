@@ -16,6 +16,11 @@ import (
 )
 
 func TestInitializationErrors(t *testing.T) {
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+	}()
+	os.Args = []string{os.Args[0]}
 	t.Run("application name must be specified rerun", func(t *testing.T) {
 		if os.Getenv("APP_TEST_CRASH") == "1" {
 			slice.Run()
@@ -33,13 +38,11 @@ func TestInitializationErrors(t *testing.T) {
 
 	t.Run("application name must be specified", func(t *testing.T) {
 		logger := &testcmp.Log{}
-		require.PanicsWithValue(t, "fatal interruption", func() {
-			slice.Run(
-				slice.WithLogger(logger),
-			)
-		}, "app should stop with panic")
-		require.Len(t, logger.FatalLogs, 1, "logger should have 1 fatal message")
-		require.Equal(t, "application name must be specified, see slice.SetName() option", logger.FatalLogs[0])
+		slice.Run(
+			slice.WithLogger(logger),
+		)
+		require.Len(t, logger.FatalLogs(), 1, "logger should have 1 fatal message")
+		require.Equal(t, "application name must be specified, see slice.SetName() option", logger.FatalLogs()[0])
 	})
 
 	t.Run("bundle without name cause error", func(t *testing.T) {
@@ -49,8 +52,8 @@ func TestInitializationErrors(t *testing.T) {
 			slice.WithLogger(logger),
 			slice.WithBundles(bundle.New()),
 		)
-		require.Len(t, logger.FatalLogs, 1)
-		require.Equal(t, "prepare bundles: bundle with index 0: empty name", logger.FatalLogs[0])
+		require.Len(t, logger.FatalLogs(), 1)
+		require.Equal(t, "prepare bundles: bundle with index 0: empty name", logger.FatalLogs()[0])
 	})
 
 	t.Run("invalid component causes error", func(t *testing.T) {
@@ -62,9 +65,9 @@ func TestInitializationErrors(t *testing.T) {
 				slice.Provide(nil),
 			),
 		)
-		require.Len(t, logger.FatalLogs, 1)
-		require.Contains(t, logger.FatalLogs[0], "initialization: create container failed: ")
-		require.Contains(t, logger.FatalLogs[0], ": invalid constructor signature, got nil")
+		require.Len(t, logger.FatalLogs(), 1)
+		require.Contains(t, logger.FatalLogs()[0], "initialization: create container failed: ")
+		require.Contains(t, logger.FatalLogs()[0], ": invalid constructor signature, got nil")
 	})
 }
 
@@ -0,0 +1,73 @@
+package slicetest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/goava/di"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/internal/intercept"
+)
+
+// Interceptor is called before lifecycle steps: BeforeStart hooks (slice.StepBoot), dispatcher start
+// (slice.StepStart) and BeforeShutdown hooks (slice.StepShutdown). Error returned by interceptor fails
+// the step. Interceptor could also delay or panic.
+type Interceptor func(ctx context.Context, step string, bundle string, name string) error
+
+// WithInterceptor adds interceptor of application lifecycle steps.
+func WithInterceptor(interceptor Interceptor) Option {
+	return option(func(a *App) {
+		a.options = append(a.options, intercept.Option(intercept.Interceptor(interceptor)).(slice.Option))
+	})
+}
+
+// FailHook makes BeforeStart hooks of bundle fail with err.
+func FailHook(bundle string, err error) Option {
+	return WithInterceptor(func(ctx context.Context, step string, b string, name string) error {
+		if step == slice.StepBoot && b == bundle {
+			return err
+		}
+		return nil
+	})
+}
+
+// DelayDispatcher delays start of dispatcher for duration d or until application context is done.
+// Dispatcher is a pointer to dispatcher type, e.g. new(*Server).
+func DelayDispatcher(dispatcher di.Pointer, d time.Duration) Option {
+	typ := typeName(dispatcher)
+	return WithInterceptor(func(ctx context.Context, step string, bundle string, name string) error {
+		if step != slice.StepStart || name != typ {
+			return nil
+		}
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+		}
+		return nil
+	})
+}
+
+// PanicConstructor makes constructor of component panic after the original constructor call.
+// Component is a pointer to component type, e.g. new(*http.Server). Application returns the panic as
+// error of the lifecycle step that resolved component: boot failure is returned by App.Start() after
+// shutdown of booted bundles, dispatcher failure stops application with error.
+func PanicConstructor(component di.Pointer) Option {
+	rt := reflect.TypeOf(component)
+	typ := typeName(component)
+	decorator := reflect.MakeFunc(reflect.FuncOf([]reflect.Type{rt.Elem()}, []reflect.Type{rt.Elem()}, false), func([]reflect.Value) []reflect.Value {
+		panic(fmt.Sprintf("slicetest: %s constructor panic", typ))
+	})
+	return WithOptions(slice.WithComponents(slice.Decorate(decorator.Interface())))
+}
+
+// typeName returns name of type that pointer points to.
+func typeName(ptr di.Pointer) string {
+	rt := reflect.TypeOf(ptr)
+	if rt == nil || rt.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("slicetest: pointer expected, got %s", rt))
+	}
+	return rt.Elem().String()
+}
@@ -0,0 +1,52 @@
+package slicetest
+
+import (
+	"github.com/goava/slice"
+)
+
+// Option configures test application.
+type Option interface {
+	apply(a *App)
+}
+
+// WithOptions adds application options, e.g. options of main package.
+func WithOptions(options ...slice.Option) Option {
+	return option(func(a *App) {
+		a.options = append(a.options, options...)
+	})
+}
+
+// WithEnv sets environment variable of application. Application does not see process environment
+// variables. ENV is "test" by default.
+func WithEnv(key string, value string) Option {
+	return option(func(a *App) {
+		a.env[key] = value
+	})
+}
+
+// WithArgs sets command line arguments of application. By default, there are no arguments.
+func WithArgs(args ...string) Option {
+	return option(func(a *App) {
+		a.args = append(a.args, args...)
+	})
+}
+
+// WithParameters sets parameter values. Parameters must be pointers to structures of parameter
+// types. Parameters that are not set are parsed from environment of application, see WithEnv().
+func WithParameters(parameters ...slice.Parameter) Option {
+	return option(func(a *App) {
+		a.parameters = append(a.parameters, parameters...)
+	})
+}
+
+// Replace replaces component provided by application or bundle with constructor result. The type of
+// component is the first result of constructor, see slice.Replace().
+func Replace(constructor interface{}) Option {
+	return option(func(a *App) {
+		a.replaces = append(a.replaces, constructor)
+	})
+}
+
+type option func(a *App)
+
+func (o option) apply(a *App) { o(a) }
@@ -0,0 +1,192 @@
+// Package slicetest runs a full application inside a test. Application gets injected environment and
+// command line arguments, its logs are captured and it is stopped on test cleanup:
+//
+//	func TestApplication(t *testing.T) {
+//		app := slicetest.Start(t,
+//			slicetest.WithOptions(
+//				slice.WithName("sliced"),
+//				slice.WithBundles(httpsrv.Bundle),
+//			),
+//			slicetest.WithEnv("DEBUG", "true"),
+//			slicetest.Replace(NewFakeMailer),
+//		)
+//		var server *http.Server
+//		app.Resolve(&server)
+//	}
+package slicetest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/goava/di"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/internal/harness"
+)
+
+// App is an application started inside a test.
+type App struct {
+	t          testing.TB
+	options    []slice.Option
+	env        map[string]string
+	args       []string
+	parameters []slice.Parameter
+	replaces   []interface{}
+
+	app       *slice.Application
+	logs      *logger
+	container *di.Container
+	done      chan error
+	lock      sync.Mutex
+	stopped   bool
+	err       error
+}
+
+// New creates application for test t. Use Start() method to start it.
+func New(t testing.TB, options ...Option) *App {
+	a := &App{
+		t:    t,
+		env:  map[string]string{"ENV": "test"},
+		logs: &logger{t: t},
+	}
+	for _, opt := range options {
+		opt.apply(a)
+	}
+	return a
+}
+
+// Start creates and starts application for test t. Start error fails the test.
+func Start(t testing.TB, options ...Option) *App {
+	t.Helper()
+	a := New(t, options...)
+	if err := a.Start(); err != nil {
+		t.Fatalf("slicetest: start failed: %s", err)
+	}
+	return a
+}
+
+// Start starts application and waits until it is running. Application is stopped on test cleanup.
+func (a *App) Start() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.app != nil {
+		return errors.New("slicetest: application already started")
+	}
+	running := make(chan *di.Container, 1)
+	lookup := func(key string) (string, bool) {
+		v, ok := a.env[key]
+		return v, ok
+	}
+	options := append([]slice.Option(nil), a.options...)
+	options = append(options,
+		slice.WithArgs(a.args...),
+		slice.WithoutSignals(),
+		slice.WithEnvLookup(lookup),
+		slice.WithLogger(a.logs),
+		slice.WithComponents(slice.Provide(harness.Probe(running))),
+	)
+	for _, constructor := range a.replaces {
+		options = append(options, slice.WithComponents(slice.Replace(constructor)))
+	}
+	if len(a.parameters) != 0 {
+		options = append(options, slice.WithParameterParser(harness.ParameterParser{Values: a.parameters, Lookup: lookup}))
+	}
+	a.app = slice.New(options...)
+	a.done = make(chan error, 1)
+	go func() {
+		defer func() {
+			// application returns panics of hooks and dispatchers as errors, other panics of start
+			// fail the start instead of the test binary
+			if r := recover(); r != nil {
+				a.done <- fmt.Errorf("slicetest: application panic: %v", r)
+			}
+		}()
+		a.done <- a.app.Start()
+	}()
+	a.t.Cleanup(func() {
+		if err := a.Stop(); err != nil {
+			a.t.Errorf("slicetest: stop failed: %s", err)
+		}
+	})
+	select {
+	case a.container = <-running:
+		return nil
+	case err := <-a.done:
+		a.stopped = true
+		select {
+		case a.container = <-running:
+			// application was running and stopped itself
+			a.err = err
+			return nil
+		default:
+		}
+		if err == nil {
+			err = errors.New("slicetest: application stopped before start")
+		}
+		return err
+	}
+}
+
+// Resolve resolves component from the application container. Resolve error fails the test.
+func (a *App) Resolve(ptr di.Pointer, options ...di.ResolveOption) {
+	a.t.Helper()
+	a.lock.Lock()
+	container := a.container
+	a.lock.Unlock()
+	if container == nil {
+		a.t.Fatalf("slicetest: application not running")
+	}
+	if err := container.Resolve(ptr, options...); err != nil {
+		a.t.Fatalf("slicetest: resolve failed: %s", err)
+	}
+}
+
+// Stop stops application and returns its shutdown error. It is safe to call Stop several times.
+func (a *App) Stop() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.app == nil || a.stopped {
+		return a.err
+	}
+	a.app.Stop()
+	a.err = <-a.done
+	a.stopped = true
+	a.container = nil
+	return a.err
+}
+
+// Logs returns captured log messages.
+func (a *App) Logs() []string {
+	return a.logs.messages()
+}
+
+// logger captures log messages and writes them to test log.
+type logger struct {
+	t    testing.TB
+	lock sync.Mutex
+	logs []string
+}
+
+// Printf implements slice.Logger interface.
+func (l *logger) Printf(bundle string, format string, values ...interface{}) {
+	message := fmt.Sprintf(format, values...)
+	l.lock.Lock()
+	l.logs = append(l.logs, message)
+	l.lock.Unlock()
+	l.t.Logf("%s: %s", bundle, message)
+}
+
+// Fatal implements slice.Logger interface.
+func (l *logger) Fatal(err error) {
+	l.Printf("slice", "%s", err)
+}
+
+// messages returns a copy of captured messages.
+func (l *logger) messages() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string(nil), l.logs...)
+}
@@ -0,0 +1,228 @@
+package slicetest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goava/di"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goava/slice"
+	"github.com/goava/slice/bundle"
+	"github.com/goava/slice/slicetest"
+)
+
+type Mailer interface {
+	Send(to string) string
+}
+
+type smtpMailer struct{}
+
+func (smtpMailer) Send(to string) string { return "smtp " + to }
+
+type fakeMailer struct{ prefix string }
+
+func (m fakeMailer) Send(to string) string { return m.prefix + to }
+
+type Parameters struct {
+	From string `envconfig:"from" default:"noreply"`
+}
+
+func TestStart(t *testing.T) {
+	mail := bundle.New(
+		bundle.WithName("mail"),
+		bundle.WithParameters(&Parameters{}),
+		bundle.WithComponents(
+			slice.Provide(func() smtpMailer { return smtpMailer{} }, di.As(new(Mailer))),
+		),
+		bundle.WithHooks(slice.Hook{
+			BeforeStart: func(logger slice.Logger, p *Parameters) {
+				logger.Printf("mail", "Sending from %s", p.From)
+			},
+		}),
+	)
+	options := slicetest.WithOptions(
+		slice.WithName("app"),
+		slice.WithBundles(mail),
+	)
+
+	t.Run("application running", func(t *testing.T) {
+		app := slicetest.Start(t, options,
+			slicetest.WithParameters(&Parameters{From: "admin"}),
+		)
+		var mailer Mailer
+		app.Resolve(&mailer)
+		require.Equal(t, "smtp user", mailer.Send("user"))
+		var info slice.Info
+		app.Resolve(&info)
+		require.True(t, info.Env.IsTest())
+		require.Contains(t, app.Logs(), "Sending from admin")
+		require.NoError(t, app.Stop())
+	})
+
+	t.Run("component replaced", func(t *testing.T) {
+		app := slicetest.Start(t, options,
+			slicetest.Replace(func(info slice.Info) Mailer { return fakeMailer{prefix: "fake "} }),
+		)
+		var mailer Mailer
+		app.Resolve(&mailer)
+		require.Equal(t, "fake user", mailer.Send("user"))
+	})
+
+	t.Run("conditions use injected environment", func(t *testing.T) {
+		app := slicetest.Start(t,
+			slicetest.WithOptions(
+				slice.WithName("app"),
+				slice.WithComponents(
+					slice.When(slice.IfEnv("MAILER", "fake"),
+						slice.Supply(fakeMailer{prefix: "fake "}, di.As(new(Mailer))),
+					),
+				),
+			),
+			slicetest.WithEnv("MAILER", "fake"),
+		)
+		var mailer Mailer
+		app.Resolve(&mailer)
+		require.Equal(t, "fake user", mailer.Send("user"))
+	})
+
+	t.Run("parameters parsed from injected environment", func(t *testing.T) {
+		app := slicetest.Start(t, options, slicetest.WithEnv("FROM", "env"))
+		require.Contains(t, app.Logs(), "Sending from env")
+		require.NoError(t, app.Stop())
+	})
+
+	t.Run("parameters not supplied parsed from injected environment", func(t *testing.T) {
+		type Other struct{}
+		app := slicetest.Start(t, options,
+			slicetest.WithEnv("FROM", "env"),
+			slicetest.WithParameters(&Other{}),
+		)
+		require.Contains(t, app.Logs(), "Sending from env")
+		require.NoError(t, app.Stop())
+	})
+
+	t.Run("parameter must be a pointer", func(t *testing.T) {
+		app := slicetest.New(t,
+			slicetest.WithOptions(
+				slice.WithName("app"),
+				slice.WithBundles(bundle.New(
+					bundle.WithName("invalid"),
+					bundle.WithParameters(Parameters{}),
+					bundle.WithHooks(slice.Hook{BeforeStart: func(p Parameters) {}}),
+				)),
+			),
+			slicetest.WithParameters(&Parameters{}),
+		)
+		err := app.Start()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parameter slicetest_test.Parameters must be a pointer")
+	})
+
+	t.Run("start error returned", func(t *testing.T) {
+		app := slicetest.New(t, slicetest.WithOptions(
+			slice.WithName("app"),
+			slice.WithBundles(bundle.New(
+				bundle.WithName("failing"),
+				bundle.WithHooks(slice.Hook{
+					BeforeStart: func() error { return errors.New("unexpected error") },
+				}),
+			)),
+		))
+		require.EqualError(t, app.Start(), "starting: - boot failing bundle failed: unexpected error\n")
+	})
+
+	t.Run("application stops on dispatcher return", func(t *testing.T) {
+		app := slicetest.Start(t, slicetest.WithOptions(
+			slice.WithName("app"),
+			slice.WithComponents(
+				slice.Supply(dispatcherFunc(func(ctx context.Context) error { return nil }), di.As(new(slice.Dispatcher))),
+			),
+		))
+		require.NoError(t, app.Stop())
+	})
+}
+
+type dispatcherFunc func(ctx context.Context) error
+
+func (f dispatcherFunc) Run(ctx context.Context) error { return f(ctx) }
+
+func TestFaults(t *testing.T) {
+	type Server struct{}
+	var shutdown bool
+	database := bundle.New(
+		bundle.WithName("database"),
+		bundle.WithHooks(slice.Hook{
+			BeforeStart:    func() {},
+			BeforeShutdown: func() { shutdown = true },
+		}),
+	)
+	api := bundle.New(
+		bundle.WithName("api"),
+		bundle.WithBundles(database),
+		bundle.WithComponents(
+			slice.Provide(func() *Server { return &Server{} }),
+		),
+		bundle.WithHooks(slice.Hook{
+			BeforeStart: func(*Server) {},
+		}),
+	)
+	options := slicetest.WithOptions(
+		slice.WithName("app"),
+		slice.WithBundles(api),
+	)
+
+	t.Run("failed hook rolls back booted bundles", func(t *testing.T) {
+		shutdown = false
+		app := slicetest.New(t, options, slicetest.FailHook("api", errors.New("injected")))
+		require.EqualError(t, app.Start(), "starting: - boot api bundle failed: injected\n")
+		require.True(t, shutdown)
+	})
+
+	t.Run("constructor panic returned as error", func(t *testing.T) {
+		shutdown = false
+		app := slicetest.New(t, options, slicetest.PanicConstructor(new(*Server)))
+		err := app.Start()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "slicetest: *slicetest_test.Server constructor panic")
+		require.True(t, shutdown)
+	})
+
+	t.Run("dispatcher constructor panic rolls back booted bundles", func(t *testing.T) {
+		shutdown = false
+		dispatcher := dispatcherFunc(func(ctx context.Context) error { return nil })
+		app := slicetest.New(t,
+			slicetest.WithOptions(
+				slice.WithName("app"),
+				slice.WithBundles(database),
+				slice.WithComponents(slice.Supply(dispatcher, di.As(new(slice.Dispatcher)))),
+			),
+			slicetest.PanicConstructor(new(dispatcherFunc)),
+		)
+		err := app.Start()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "dispatch failed: panic: slicetest: slicetest_test.dispatcherFunc constructor panic")
+		require.True(t, shutdown)
+	})
+
+	t.Run("dispatcher delayed", func(t *testing.T) {
+		started := make(chan time.Time, 1)
+		dispatcher := dispatcherFunc(func(ctx context.Context) error {
+			started <- time.Now()
+			return nil
+		})
+		start := time.Now()
+		slicetest.Start(t,
+			slicetest.WithOptions(
+				slice.WithName("app"),
+				slice.WithComponents(
+					slice.Supply(dispatcher, di.As(new(slice.Dispatcher))),
+				),
+			),
+			slicetest.DelayDispatcher(new(dispatcherFunc), 20*time.Millisecond),
+		)
+		require.True(t, (<-started).Sub(start) >= 20*time.Millisecond)
+	})
+}
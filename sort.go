@@ -1,7 +1,10 @@
 package slice
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 )
 
 const (
@@ -9,42 +12,243 @@ const (
 	permanent = 2
 )
 
-// prepareBundles is a step of application bootstrap.
-func prepareBundles(bundles []Bundle) ([]Bundle, error) {
-	var sorted []Bundle
-	marks := map[string]int{}
+// prepareBundles is a step of application bootstrap. It checks bundles and sorts them in the boot
+// order. The order is stable: the same input always gives the same order.
+//
+// Bundles are sorted in depth-first order. Dependencies of a bundle (Bundle.Bundles) go before the
+// bundle in their declaration order. Then go present bundles listed in Bundle.After. Root bundles
+// keep the order of registration. A bundle that is reachable several times takes the place of its
+// first occurrence.
+//
+// Bundles are identified by name. Different bundles with the same name cause error. Bundles
+// disabled by their condition are skipped with their dependencies, unless dependencies are
+// required by enabled bundles. Inferred contains additional ordering dependencies by bundle name,
+// see slice.InferBundleOrder().
+func prepareBundles(bundles []Bundle, info Info, inferred map[string][]string) ([]Bundle, error) {
+	s := &sorter{
+		info:     info,
+		inferred: inferred,
+		marks:    map[string]int{},
+		seen:     map[string]Bundle{},
+	}
+	var roots []Bundle
 	for i, b := range bundles {
 		if b.Name == "" {
 			return nil, fmt.Errorf("bundle with index %d: empty name", i)
 		}
-		if !visit(b, marks, &sorted) {
-			return sorted, fmt.Errorf("bundle cyclic detected") // todo: improve error message
+		if !b.enabled(info) {
+			continue
+		}
+		if err := s.collect(b, ""); err != nil {
+			return nil, err
+		}
+		roots = append(roots, b)
+	}
+	for _, b := range roots {
+		if err := s.visit(b, nil); err != nil {
+			var cycle errCycle
+			if errors.As(err, &cycle) {
+				return s.sorted, fmt.Errorf("%w (root bundle %s)", err, b.Name)
+			}
+			return s.sorted, err
+		}
+	}
+	if err := checkConstraints(s.sorted); err != nil {
+		return s.sorted, err
+	}
+	return s.sorted, nil
+}
+
+// sorter sorts bundles with depth-first topological sort algorithm.
+type sorter struct {
+	info     Info
+	inferred map[string][]string
+	marks    map[string]int
+	seen     map[string]Bundle
+	sorted   []Bundle
+}
+
+// collect collects enabled bundles and checks their names.
+func (s *sorter) collect(b Bundle, parent string) error {
+	if b.Name == "" {
+		return fmt.Errorf("dependency of %s: empty name", parent)
+	}
+	if seen, ok := s.seen[b.Name]; ok {
+		if !sameBundle(seen, b) {
+			return fmt.Errorf("bundle name conflict: different bundles named %s", b.Name)
+		}
+		return nil
+	}
+	s.seen[b.Name] = b
+	for _, dep := range b.Bundles {
+		if !dep.enabled(s.info) {
+			continue
+		}
+		if err := s.collect(dep, b.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dependencies returns bundles that should boot before b.
+func (s *sorter) dependencies(b Bundle) (deps []Bundle) {
+	for _, dep := range b.Bundles {
+		if dep.enabled(s.info) {
+			deps = append(deps, dep)
+		}
+	}
+	for _, name := range b.After {
+		if dep, ok := s.seen[name]; ok {
+			deps = append(deps, dep)
+		}
+	}
+	for _, name := range s.inferred[b.Name] {
+		if dep, ok := s.seen[name]; ok {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// visit visits bundle dependencies in depth-first order. Stack contains names of visiting bundles.
+func (s *sorter) visit(b Bundle, stack []string) error {
+	if s.marks[b.Name] == permanent {
+		return nil
+	}
+	if s.marks[b.Name] == temporary {
+		// cut the stack from the first bundle occurrence
+		for i, name := range stack {
+			if name == b.Name {
+				return errCycle(append(stack[i:len(stack):len(stack)], b.Name))
+			}
+		}
+	}
+	s.marks[b.Name] = temporary
+	stack = append(stack, b.Name)
+	for _, dep := range s.dependencies(b) {
+		if err := s.visit(dep, stack); err != nil {
+			return err
 		}
 	}
-	return sorted, nil
+	s.marks[b.Name] = permanent
+	s.sorted = append(s.sorted, b)
+	return nil
+}
+
+// errCycle contains bundle cycle path.
+type errCycle []string
+
+// Error implements error interface.
+func (e errCycle) Error() string {
+	return fmt.Sprintf("bundle cycle detected: %s", strings.Join(e, " -> "))
 }
 
-// visit
-func visit(b Bundle, marks map[string]int, sorted *[]Bundle) bool {
-	if marks[b.Name] == permanent {
-		return true
+// sameBundle checks that a and b declare the same bundle. Bundles built by separate calls of the
+// same constructor are the same bundle if their parameters and supplied values are deeply equal.
+func sameBundle(a, b Bundle) bool {
+	declA, valuesA := declaration(a)
+	declB, valuesB := declaration(b)
+	return a.Name == b.Name && declA == declB && reflect.DeepEqual(valuesA, valuesB)
+}
+
+// declaration describes bundle content and returns its parameters and supplied values. Function
+// values are not comparable, so functions are described by their names and types: closures of
+// the same function with different captured values have the same declaration.
+func declaration(b Bundle) (string, []interface{}) {
+	var d strings.Builder
+	var values []interface{}
+	fmt.Fprintf(&d, "%s %s %s %s %s %t %s", b.instance, b.Description, b.Version, b.Owner, b.URL, b.Optional, describeFunc(b.Condition))
+	for _, p := range b.Parameters {
+		fmt.Fprintf(&d, "|parameter %T", p)
+		values = append(values, p)
+	}
+	app := &Application{}
+	for _, option := range b.Components {
+		option.apply(app)
+	}
+	for _, c := range app.components {
+		if c.supplied {
+			fmt.Fprintf(&d, "|supply %T %t", c.value, c.private)
+			values = append(values, c.value)
+			continue
+		}
+		fmt.Fprintf(&d, "|provide %s %t", describeFunc(c.constructor), c.private)
+	}
+	for _, dec := range app.decorators {
+		fmt.Fprintf(&d, "|decorate %s", describeFunc(dec.fn))
+	}
+	for _, r := range app.replacements {
+		fmt.Fprintf(&d, "|replace %s", describeFunc(r.constructor))
 	}
-	if marks[b.Name] == temporary {
-		// acyclic
-		return false
+	fmt.Fprintf(&d, "|conditionals %d", len(app.conditionals))
+	for _, h := range b.Hooks {
+		fmt.Fprintf(&d, "|hook %s %s %v", describeFunc(h.BeforeStart), describeFunc(h.BeforeShutdown), h.Retry)
 	}
-	if len(b.Bundles) == 0 {
-		marks[b.Name] = permanent
-		*sorted = append([]Bundle{b}, *sorted...)
-		return true
+	for _, c := range b.Checks {
+		fmt.Fprintf(&d, "|check %s %s %t", c.Name, describeFunc(c.Run), c.Warning)
 	}
-	marks[b.Name] = temporary
 	for _, dep := range b.Bundles {
-		if !visit(dep, marks, sorted) {
-			return false
+		fmt.Fprintf(&d, "|bundle %s", dep.Name)
+	}
+	for _, p := range b.Requires {
+		fmt.Fprintf(&d, "|requires %T", p)
+	}
+	for _, p := range b.Provides {
+		fmt.Fprintf(&d, "|provides %T", p)
+	}
+	fmt.Fprintf(&d, "|after %v|constraints %v", b.After, b.Constraints)
+	return d.String(), values
+}
+
+// describeFunc returns function name and type.
+func describeFunc(fn interface{}) string {
+	if rv := reflect.ValueOf(fn); !rv.IsValid() || rv.Kind() == reflect.Func && rv.IsNil() {
+		return "nil"
+	}
+	return fmt.Sprintf("%s %T", funcName(fn), fn)
+}
+
+// inferBundleOrder returns ordering dependencies between bundles by consumed and provided types
+// of their components, hooks and checks.
+func inferBundleOrder(bundles []Bundle, components []component) map[string][]string {
+	providers := map[reflect.Type][]string{}
+	consumers := map[string][]reflect.Type{}
+	candidates := consumedTypes(bundles, components)
+	for _, c := range components {
+		if c.bundle == "" {
+			continue
+		}
+		// private components provide types to their own bundle only
+		for _, t := range c.types(candidates) {
+			if c.private {
+				continue
+			}
+			providers[t] = append(providers[t], c.bundle)
+		}
+		consumers[c.bundle] = append(consumers[c.bundle], c.dependencies()...)
+	}
+	for _, b := range bundles {
+		for _, h := range b.Hooks {
+			consumers[b.Name] = append(consumers[b.Name], invocationDependencies(h.BeforeStart)...)
+			consumers[b.Name] = append(consumers[b.Name], invocationDependencies(h.BeforeShutdown)...)
+		}
+		for _, c := range b.Checks {
+			consumers[b.Name] = append(consumers[b.Name], invocationDependencies(c.Run)...)
+		}
+	}
+	inferred := map[string][]string{}
+	for _, b := range bundles {
+		added := map[string]bool{}
+		for _, t := range consumers[b.Name] {
+			for _, provider := range providers[t] {
+				if provider == b.Name || added[provider] {
+					continue
+				}
+				added[provider] = true
+				inferred[b.Name] = append(inferred[b.Name], provider)
+			}
 		}
 	}
-	marks[b.Name] = permanent
-	*sorted = append([]Bundle{b}, *sorted...)
-	return true
+	return inferred
 }
@@ -2,8 +2,10 @@ package slice
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/goava/di"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,32 +22,253 @@ func (p bundleNames) Names() []string {
 func Test_sortBundles(t *testing.T) {
 	t.Run("dependency bundle added to list in correct order", func(t *testing.T) {
 		bundles := []Bundle{third}
-		result, err := prepareBundles(bundles)
+		result, err := prepareBundles(bundles, Info{}, nil)
 		require.NoError(t, err)
 		require.Len(t, result, 4)
-		require.Equal(t, []Bundle{third, four, second, first}, result)
+		require.Equal(t, []Bundle{first, second, four, third}, result)
 		fmt.Println(bundleNames(result).Names())
 	})
 
 	t.Run("duplicate bundles filtered correctly", func(t *testing.T) {
 		bundles := []Bundle{first, second, four}
-		result, err := prepareBundles(bundles)
+		result, err := prepareBundles(bundles, Info{}, nil)
 		require.NoError(t, err)
 		require.Len(t, result, 3)
-		require.Equal(t, []Bundle{four, second, first}, result)
+		require.Equal(t, []Bundle{first, second, four}, result)
 		fmt.Println(bundleNames(result).Names())
 	})
 
 	t.Run("chaos check", func(t *testing.T) {
 		bundles := []Bundle{first, second, third, first, second, third, first, second, first, second}
-		result, err := prepareBundles(bundles)
+		result, err := prepareBundles(bundles, Info{}, nil)
 		require.NoError(t, err)
 		require.Len(t, result, 4)
-		require.Equal(t, []Bundle{third, four, second, first}, result)
+		require.Equal(t, []Bundle{first, second, four, third}, result)
 		fmt.Println(bundleNames(result).Names())
 	})
 }
 
+func Test_sortBundlesOrder(t *testing.T) {
+	t.Run("dependencies go before bundle", func(t *testing.T) {
+		result, err := prepareBundles([]Bundle{four, third, second}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1:[]", "4:1", "2:1", "3:2,4"}, bundleNames(result).Names())
+	})
+
+	t.Run("siblings keep declaration order", func(t *testing.T) {
+		a := Bundle{Name: "a"}
+		b := Bundle{Name: "b"}
+		c := Bundle{Name: "c", Bundles: []Bundle{b, a}}
+		result, err := prepareBundles([]Bundle{c, a}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"b", "a", "c"}, bundleNames(result).Names())
+	})
+}
+
+func Test_sortBundlesAfter(t *testing.T) {
+	metrics := Bundle{Name: "metrics"}
+	db := Bundle{Name: "db", After: []string{"metrics"}}
+
+	t.Run("bundle boots after present bundle", func(t *testing.T) {
+		result, err := prepareBundles([]Bundle{db, metrics}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"metrics", "db"}, bundleNames(result).Names())
+	})
+
+	t.Run("absent bundle not added", func(t *testing.T) {
+		result, err := prepareBundles([]Bundle{db}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"db"}, bundleNames(result).Names())
+	})
+
+	t.Run("soft dependency of nested bundle", func(t *testing.T) {
+		repo := Bundle{Name: "repo", Bundles: []Bundle{db}}
+		http := Bundle{Name: "http", Bundles: []Bundle{metrics}}
+		result, err := prepareBundles([]Bundle{repo, http}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"metrics", "db", "repo", "http"}, bundleNames(result).Names())
+	})
+
+	t.Run("soft cycle reported", func(t *testing.T) {
+		a := Bundle{Name: "a", After: []string{"b"}}
+		b := Bundle{Name: "b", Bundles: []Bundle{a}}
+		_, err := prepareBundles([]Bundle{b}, Info{}, nil)
+		require.EqualError(t, err, "bundle cycle detected: b -> a -> b (root bundle b)")
+	})
+}
+
+func Test_inferBundleOrder(t *testing.T) {
+	db := Bundle{
+		Name: "db",
+		Components: []ComponentOption{
+			Supply(&http.Client{}),
+		},
+	}
+	server := Bundle{
+		Name: "http",
+		Components: []ComponentOption{
+			Provide(func(client *http.Client) *http.ServeMux { return http.NewServeMux() }, di.As(new(http.Handler))),
+		},
+	}
+	cron := Bundle{
+		Name: "cron",
+		Hooks: []Hook{{
+			BeforeStart: func(handlers []http.Handler) {},
+		}},
+	}
+
+	t.Run("bundle boots after provider", func(t *testing.T) {
+		bundles := []Bundle{cron, server, db}
+		inferred := inferBundleOrder(bundles, bundleComponents(bundles...))
+		require.Equal(t, map[string][]string{"http": {"db"}, "cron": {"http"}}, inferred)
+		result, err := prepareBundles(bundles, Info{}, inferred)
+		require.NoError(t, err)
+		require.Equal(t, []string{"db", "http", "cron"}, bundleNames(result).Names())
+	})
+
+	t.Run("injected fields are dependencies", func(t *testing.T) {
+		type handlers struct {
+			di.Inject
+			Handlers []http.Handler
+		}
+		api := Bundle{
+			Name: "api",
+			Components: []ComponentOption{
+				Provide(func(h handlers) *http.Server { return &http.Server{} }),
+			},
+		}
+		bundles := []Bundle{api, server, db}
+		inferred := inferBundleOrder(bundles, bundleComponents(bundles...))
+		require.Equal(t, []string{"http"}, inferred["api"])
+	})
+
+	t.Run("interfaces of provide params detected", func(t *testing.T) {
+		named := Bundle{
+			Name: "http",
+			Components: []ComponentOption{
+				Provide(http.NewServeMux, di.WithName("mux"), di.ProvideParams{Interfaces: []di.Interface{new(http.Handler)}}),
+			},
+		}
+		bundles := []Bundle{cron, named}
+		require.Equal(t, map[string][]string{"cron": {"http"}}, inferBundleOrder(bundles, bundleComponents(bundles...)))
+	})
+
+	t.Run("private components do not order bundles", func(t *testing.T) {
+		a := Bundle{
+			Name: "a",
+			Components: []ComponentOption{
+				Private(Provide(func() *pool { return &pool{} })),
+				Provide(func(p *pool) *repository { return &repository{pool: p} }),
+			},
+		}
+		b := Bundle{
+			Name: "b",
+			Components: []ComponentOption{
+				Private(Provide(func() *pool { return &pool{} })),
+			},
+			Hooks: []Hook{{BeforeStart: func(p *pool) {}}},
+		}
+		bundles := []Bundle{a, b}
+		inferred := inferBundleOrder(bundles, bundleComponents(bundles...))
+		require.Empty(t, inferred)
+		_, err := prepareBundles(bundles, Info{}, inferred)
+		require.NoError(t, err)
+	})
+
+	t.Run("inferred cycle reported", func(t *testing.T) {
+		consumer := Bundle{
+			Name: "consumer",
+			Components: []ComponentOption{
+				Provide(func(mux *http.ServeMux) *http.Client { return &http.Client{} }),
+			},
+		}
+		bundles := []Bundle{server, consumer}
+		_, err := prepareBundles(bundles, Info{}, inferBundleOrder(bundles, bundleComponents(bundles...)))
+		require.EqualError(t, err, "bundle cycle detected: http -> consumer -> http (root bundle http)")
+	})
+}
+
+func Test_sortBundlesConflict(t *testing.T) {
+	t.Run("different bundles with the same name cause error", func(t *testing.T) {
+		db := Bundle{Name: "db", Hooks: []Hook{{BeforeStart: func() {}}}}
+		otherDB := Bundle{Name: "db", Hooks: []Hook{{BeforeStart: func() {}}}}
+		repo := Bundle{Name: "repo", Bundles: []Bundle{otherDB}}
+		_, err := prepareBundles([]Bundle{db, repo}, Info{}, nil)
+		require.EqualError(t, err, "bundle name conflict: different bundles named db")
+	})
+
+	t.Run("bundles with different hook functions cause error", func(t *testing.T) {
+		db := Bundle{Name: "db", Hooks: []Hook{{BeforeStart: migrate}}}
+		otherDB := Bundle{Name: "db", Hooks: []Hook{{BeforeStart: migrate, BeforeShutdown: migrate}}}
+		_, err := prepareBundles([]Bundle{db, otherDB}, Info{}, nil)
+		require.EqualError(t, err, "bundle name conflict: different bundles named db")
+	})
+
+	t.Run("bundles built by separate calls merged", func(t *testing.T) {
+		newDB := func(dsn string) Bundle {
+			return Bundle{
+				Name:       "db",
+				Parameters: []Parameter{&struct{ DSN string }{DSN: dsn}},
+				Components: []ComponentOption{Supply(&http.Client{})},
+				Hooks:      []Hook{{BeforeStart: func() error { return nil }}},
+			}
+		}
+		repo := Bundle{Name: "repo", Bundles: []Bundle{newDB("postgres")}}
+		result, err := prepareBundles([]Bundle{newDB("postgres"), repo}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"db", "repo"}, bundleNames(result).Names())
+	})
+
+	t.Run("bundles with different parameter values cause error", func(t *testing.T) {
+		newDB := func(dsn string) Bundle {
+			return Bundle{
+				Name:       "db",
+				Parameters: []Parameter{&struct{ DSN string }{DSN: dsn}},
+			}
+		}
+		repo := Bundle{Name: "repo", Bundles: []Bundle{newDB("mysql")}}
+		_, err := prepareBundles([]Bundle{newDB("postgres"), repo}, Info{}, nil)
+		require.EqualError(t, err, "bundle name conflict: different bundles named db")
+	})
+
+	t.Run("bundles with different supplied values cause error", func(t *testing.T) {
+		newDB := func(dsn string) Bundle {
+			return Bundle{Name: "db", Components: []ComponentOption{Supply(dsn)}}
+		}
+		repo := Bundle{Name: "repo", Bundles: []Bundle{newDB("mysql")}}
+		_, err := prepareBundles([]Bundle{newDB("postgres"), repo}, Info{}, nil)
+		require.EqualError(t, err, "bundle name conflict: different bundles named db")
+	})
+
+	t.Run("copies of the same bundle merged", func(t *testing.T) {
+		db := Bundle{Name: "db", Hooks: []Hook{{BeforeStart: func() {}}}}
+		copied := db
+		repo := Bundle{Name: "repo", Bundles: []Bundle{copied}}
+		result, err := prepareBundles([]Bundle{db, repo}, Info{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"db", "repo"}, bundleNames(result).Names())
+	})
+}
+
+func Test_sortBundlesCycle(t *testing.T) {
+	t.Run("cycle path reported", func(t *testing.T) {
+		http := Bundle{Name: "http", Bundles: make([]Bundle, 1)}
+		session := Bundle{Name: "session", Bundles: []Bundle{http}}
+		auth := Bundle{Name: "auth", Bundles: []Bundle{session}}
+		http.Bundles[0] = auth
+		api := Bundle{Name: "api", Bundles: []Bundle{first, http}}
+		_, err := prepareBundles([]Bundle{first, api}, Info{}, nil)
+		require.EqualError(t, err, "bundle cycle detected: http -> auth -> session -> http (root bundle api)")
+	})
+
+	t.Run("self dependency reported", func(t *testing.T) {
+		self := Bundle{Name: "self", Bundles: make([]Bundle, 1)}
+		self.Bundles[0] = self
+		_, err := prepareBundles([]Bundle{self}, Info{}, nil)
+		require.EqualError(t, err, "bundle cycle detected: self -> self (root bundle self)")
+	})
+}
+
 var (
 	first = Bundle{
 		Name: "1:[]",
@@ -70,3 +293,5 @@ var (
 		},
 	}
 )
+
+func migrate() {}
@@ -0,0 +1,69 @@
+package testcmp
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a fake clock controlled by test. It implements slice.Clock interface.
+type Clock struct {
+	lock    sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+// waiter is a channel that receives time at the moment.
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewClock creates fake clock with current time now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now implements slice.Clock interface.
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// After implements slice.Clock interface. The channel receives time when clock is advanced
+// for the duration.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, waiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves clock forward for the duration and fires expired timers.
+func (c *Clock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	var waiters []waiter
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = waiters
+}
+
+// Waiters returns the number of pending timers. Tests could use it to wait until code under test
+// starts waiting before advancing the clock.
+func (c *Clock) Waiters() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.waiters)
+}
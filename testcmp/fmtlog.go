@@ -1,20 +1,43 @@
 package testcmp
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
+// FmtLog records messages and prints them to stdout. It is safe for concurrent use,
+// Fatal does not panic.
 type FmtLog struct {
-	PrintLogs []string
-	FatalLogs []string
+	printLogs []string
+	fatalLogs []string
+	lock      sync.Mutex
 }
 
 func (l *FmtLog) Printf(bundle string, format string, values ...interface{}) {
 	s := fmt.Sprintf(format, values...)
-	l.PrintLogs = append(l.PrintLogs, s)
+	l.lock.Lock()
+	l.printLogs = append(l.printLogs, s)
+	l.lock.Unlock()
 	fmt.Println(s)
 }
 
 func (l *FmtLog) Fatal(err error) {
-	l.FatalLogs = append(l.FatalLogs, err.Error())
+	l.lock.Lock()
+	l.fatalLogs = append(l.fatalLogs, err.Error())
+	l.lock.Unlock()
 	fmt.Println(err.Error())
-	//panic("fatal interruption")
+}
+
+// PrintLogs returns a copy of printed messages.
+func (l *FmtLog) PrintLogs() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string(nil), l.printLogs...)
+}
+
+// FatalLogs returns a copy of fatal errors.
+func (l *FmtLog) FatalLogs() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string(nil), l.fatalLogs...)
 }
@@ -0,0 +1,38 @@
+package testcmp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// updateGolden is an environment variable that rewrites golden files with actual values:
+// UPDATE_GOLDEN=true go test ./...
+const updateGolden = "UPDATE_GOLDEN"
+
+// AssertGolden compares actual with testdata/<name>.golden file. With UPDATE_GOLDEN=true environment
+// variable the golden file is rewritten with actual value.
+func AssertGolden(t testing.TB, name string, actual string) bool {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if update, _ := strconv.ParseBool(os.Getenv(updateGolden)); update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("update golden file: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatalf("update golden file: %s", err)
+		}
+		return true
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %s, run tests with UPDATE_GOLDEN=true to create it", err)
+	}
+	if string(expected) != actual {
+		t.Errorf("%s mismatch, run tests with UPDATE_GOLDEN=true to update it\nexpected:\n%s\nactual:\n%s", path, expected, actual)
+		return false
+	}
+	return true
+}
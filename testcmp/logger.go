@@ -3,21 +3,42 @@ package testcmp
 import (
 	"fmt"
 	"log"
+	"sync"
 )
 
+// Log records messages and writes them with standard logger. It is safe for concurrent use,
+// Fatal does not panic.
 type Log struct {
-	PrintLogs []string
-	FatalLogs []string
+	printLogs []string
+	fatalLogs []string
+	lock      sync.Mutex
 }
 
 func (l *Log) Printf(bundle string, format string, values ...interface{}) {
 	s := fmt.Sprintf(format, values...)
-	l.PrintLogs = append(l.PrintLogs, s)
+	l.lock.Lock()
+	l.printLogs = append(l.printLogs, s)
+	l.lock.Unlock()
 	log.Printf(s)
 }
 
 func (l *Log) Fatal(err error) {
-	l.FatalLogs = append(l.FatalLogs, err.Error())
+	l.lock.Lock()
+	l.fatalLogs = append(l.fatalLogs, err.Error())
+	l.lock.Unlock()
 	log.Printf(err.Error())
-	panic("fatal interruption")
+}
+
+// PrintLogs returns a copy of printed messages.
+func (l *Log) PrintLogs() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string(nil), l.printLogs...)
+}
+
+// FatalLogs returns a copy of fatal errors.
+func (l *Log) FatalLogs() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string(nil), l.fatalLogs...)
 }
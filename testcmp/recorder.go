@@ -0,0 +1,136 @@
+package testcmp
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Entry is a recorded log message.
+type Entry struct {
+	Bundle  string
+	Message string
+	Time    time.Time
+	// Fatal is true for messages logged with Fatal method.
+	Fatal bool
+}
+
+// String returns entry as "bundle: message".
+func (e Entry) String() string {
+	return fmt.Sprintf("%s: %s", e.Bundle, e.Message)
+}
+
+// Recorder is a concurrency-safe logger that records messages. Zero value is ready to use.
+type Recorder struct {
+	lock    sync.Mutex
+	entries []Entry
+	// changed is closed when new entry recorded
+	changed chan struct{}
+}
+
+// Printf implements slice.Logger interface.
+func (r *Recorder) Printf(bundle string, format string, values ...interface{}) {
+	r.record(Entry{Bundle: bundle, Message: fmt.Sprintf(format, values...), Time: time.Now()})
+}
+
+// Fatal implements slice.Logger interface. Unlike Log, it does not panic.
+func (r *Recorder) Fatal(err error) {
+	r.record(Entry{Bundle: "fatal", Message: err.Error(), Time: time.Now(), Fatal: true})
+}
+
+// Entries returns a copy of recorded entries.
+func (r *Recorder) Entries() []Entry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// AssertLogged checks that bundle logged message that matches regular expression pattern.
+// Empty bundle matches any bundle.
+func (r *Recorder) AssertLogged(t testing.TB, bundle string, pattern string) bool {
+	t.Helper()
+	re := regexp.MustCompile(pattern)
+	if _, ok := r.find(bundle, re, 0); ok {
+		return true
+	}
+	t.Errorf("message %q of bundle %q not logged, recorded:\n%s", pattern, bundle, r)
+	return false
+}
+
+// WaitFor waits until bundle logs message that matches regular expression pattern. Empty bundle
+// matches any bundle.
+func (r *Recorder) WaitFor(t testing.TB, bundle string, pattern string, timeout time.Duration) bool {
+	t.Helper()
+	re := regexp.MustCompile(pattern)
+	deadline := time.After(timeout)
+	for {
+		r.lock.Lock()
+		if r.changed == nil {
+			r.changed = make(chan struct{})
+		}
+		changed := r.changed
+		r.lock.Unlock()
+		if _, ok := r.find(bundle, re, 0); ok {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			t.Errorf("message %q of bundle %q not logged in %s, recorded:\n%s", pattern, bundle, timeout, r)
+			return false
+		}
+	}
+}
+
+// AssertSequence checks that messages matching regular expression patterns were logged in order.
+// Patterns are matched against "bundle: message" strings, other messages between them are ignored.
+func (r *Recorder) AssertSequence(t testing.TB, patterns ...string) bool {
+	t.Helper()
+	from := 0
+	for _, pattern := range patterns {
+		i, ok := r.find("", regexp.MustCompile(pattern), from)
+		if !ok {
+			t.Errorf("message %q not logged after position %d, recorded:\n%s", pattern, from, r)
+			return false
+		}
+		from = i + 1
+	}
+	return true
+}
+
+// String returns recorded entries, one per line.
+func (r *Recorder) String() (s string) {
+	for _, e := range r.Entries() {
+		s += fmt.Sprintf("%s\n", e)
+	}
+	return s
+}
+
+// record appends entry and notifies waiters.
+func (r *Recorder) record(e Entry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.entries = append(r.entries, e)
+	if r.changed != nil {
+		close(r.changed)
+		r.changed = nil
+	}
+}
+
+// find returns index of the first entry since from that matches bundle and pattern. Bundle entries
+// are matched by message, others by "bundle: message" string.
+func (r *Recorder) find(bundle string, re *regexp.Regexp, from int) (int, bool) {
+	entries := r.Entries()
+	for i := from; i < len(entries); i++ {
+		e := entries[i]
+		switch {
+		case bundle != "" && e.Bundle == bundle && re.MatchString(e.Message):
+			return i, true
+		case bundle == "" && re.MatchString(e.String()):
+			return i, true
+		}
+	}
+	return 0, false
+}
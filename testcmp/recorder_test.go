@@ -0,0 +1,68 @@
+package testcmp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("concurrent messages recorded", func(t *testing.T) {
+		r := &Recorder{}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				r.Printf("worker", "message %d", i)
+			}(i)
+		}
+		wg.Wait()
+		require.Len(t, r.Entries(), 10)
+		r.AssertLogged(t, "worker", `^message \d$`)
+	})
+
+	t.Run("fatal does not panic", func(t *testing.T) {
+		r := &Recorder{}
+		r.Fatal(errors.New("unexpected error"))
+		entries := r.Entries()
+		require.Len(t, entries, 1)
+		require.True(t, entries[0].Fatal)
+		require.Equal(t, "unexpected error", entries[0].Message)
+	})
+
+	t.Run("wait for message", func(t *testing.T) {
+		r := &Recorder{}
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			r.Printf("server", "Listening on :8080")
+		}()
+		r.WaitFor(t, "server", "Listening", time.Second)
+	})
+
+	t.Run("sequence", func(t *testing.T) {
+		r := &Recorder{}
+		r.Printf("slice", "Starting")
+		r.Printf("db", "Connected")
+		r.Printf("slice", "Stopped")
+		r.AssertSequence(t, "slice: Starting", "db: ", "Stopped")
+		mock := &mockT{TB: t}
+		require.False(t, r.AssertSequence(mock, "Stopped", "Starting"))
+		require.True(t, mock.failed)
+	})
+}
+
+// mockT records test failure.
+type mockT struct {
+	testing.TB
+	failed bool
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...interface{}) {
+	m.failed = true
+}
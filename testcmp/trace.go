@@ -0,0 +1,36 @@
+package testcmp
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Trace records application lifecycle steps. It implements slice.Tracer interface.
+type Trace struct {
+	lock  sync.Mutex
+	steps []string
+}
+
+// Trace implements slice.Tracer interface.
+func (t *Trace) Trace(step string, bundle string, name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.steps = append(t.steps, fmt.Sprintf("%s %s %s", step, bundle, name))
+}
+
+// Steps returns recorded steps as "step bundle name" strings.
+func (t *Trace) Steps() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]string(nil), t.steps...)
+}
+
+// String returns recorded steps, one per line.
+func (t *Trace) String() string {
+	steps := t.Steps()
+	if len(steps) == 0 {
+		return ""
+	}
+	return strings.Join(steps, "\n") + "\n"
+}
@@ -0,0 +1,28 @@
+package slice
+
+// Lifecycle steps reported to Tracer.
+const (
+	// StepBoot is reported before BeforeStart hook of bundle.
+	StepBoot = "boot"
+	// StepStart is reported before dispatcher start.
+	StepStart = "start"
+	// StepSignal is reported when application receives os signal or Application.Stop() is called.
+	StepSignal = "signal"
+	// StepStop is reported when dispatcher stopped.
+	StepStop = "stop"
+	// StepShutdown is reported before BeforeShutdown hook of bundle.
+	StepShutdown = "shutdown"
+)
+
+// Tracer observes application lifecycle steps, see slice.WithTracer(). Bundle is a name of bundle or
+// "slice" for application steps. Name is a hook function, dispatcher type or signal name. Dispatchers
+// are run concurrently, so their steps could be reported in any order.
+type Tracer interface {
+	Trace(step string, bundle string, name string)
+}
+
+// nopTracer ignores lifecycle steps.
+type nopTracer struct{}
+
+// Trace implements Tracer interface.
+func (nopTracer) Trace(step string, bundle string, name string) {}
@@ -0,0 +1,177 @@
+package slice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Constraint restricts version of another bundle, see bundle.WithConstraint().
+type Constraint struct {
+	// Bundle is a name of constrained bundle.
+	Bundle string
+	// Version is a comma-separated list of comparisons, e.g. ">= 1.3, < 2".
+	// Supported operators: =, !=, >, >=, <, <=. Version without operator means equality.
+	Version string
+}
+
+// checkConstraints checks version constraints between bundles. Constraints on absent bundles are ignored.
+func checkConstraints(bundles []Bundle) error {
+	versions := map[string]string{}
+	for _, b := range bundles {
+		versions[b.Name] = b.Version
+	}
+	for _, b := range bundles {
+		for _, c := range b.Constraints {
+			version, ok := versions[c.Bundle]
+			if !ok {
+				continue
+			}
+			if version == "" {
+				return fmt.Errorf("bundle %s requires %s %s: %s has no version", b.Name, c.Bundle, c.Version, c.Bundle)
+			}
+			satisfied, err := satisfies(version, c.Version)
+			if err != nil {
+				return fmt.Errorf("bundle %s requires %s %s: %w", b.Name, c.Bundle, c.Version, err)
+			}
+			if !satisfied {
+				return fmt.Errorf("bundle %s requires %s %s, got %s", b.Name, c.Bundle, c.Version, version)
+			}
+		}
+	}
+	return nil
+}
+
+// satisfies checks that version satisfies all comparisons of constraint.
+func satisfies(version string, constraint string) (bool, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	for _, comparison := range strings.Split(constraint, ",") {
+		comparison = strings.TrimSpace(comparison)
+		op := strings.TrimRight(comparison[:len(comparison)-len(strings.TrimLeft(comparison, "=!<>"))], " ")
+		c, err := parseVersion(strings.TrimSpace(comparison[len(op):]))
+		if err != nil {
+			return false, err
+		}
+		cmp := v.compare(c)
+		var ok bool
+		switch op {
+		case "", "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		default:
+			return false, fmt.Errorf("invalid constraint operator %q", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// version is a semantic version.
+type version struct {
+	numbers    [3]int
+	prerelease string
+}
+
+// parseVersion parses semantic version. Leading "v", minor and patch numbers are optional,
+// build metadata is ignored.
+func parseVersion(s string) (v version, _ error) {
+	str := strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(str, '+'); i >= 0 {
+		str = str[:i]
+	}
+	if i := strings.IndexByte(str, '-'); i >= 0 {
+		v.prerelease = str[i+1:]
+		str = str[:i]
+	}
+	parts := strings.Split(str, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v.numbers[i] = n
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or 1 if v less, equal or greater than other. Pre-release version has lower
+// precedence than release.
+func (v version) compare(other version) int {
+	for i := range v.numbers {
+		if c := compareInts(v.numbers[i], other.numbers[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case v.prerelease == other.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case other.prerelease == "":
+		return -1
+	}
+	return comparePrerelease(strings.Split(v.prerelease, "."), strings.Split(other.prerelease, "."))
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers. Numeric identifiers are compared
+// as numbers and have lower precedence than alphanumeric ones. Larger set of identifiers has higher
+// precedence if all preceding identifiers are equal.
+func comparePrerelease(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		an, anum := numericIdentifier(a[i])
+		bn, bnum := numericIdentifier(b[i])
+		switch {
+		case anum && bnum:
+			if c := compareInts(an, bn); c != 0 {
+				return c
+			}
+		case anum:
+			return -1
+		case bnum:
+			return 1
+		case a[i] != b[i]:
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return compareInts(len(a), len(b))
+}
+
+// numericIdentifier parses pre-release identifier that contains only digits.
+func numericIdentifier(s string) (int, bool) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// compareInts returns -1, 0 or 1 if a less, equal or greater than b.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package slice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.3.0", ">= 1.3", true},
+		{"v1.4.2", ">=1.3, <2", true},
+		{"2.0.0", ">= 1.3, < 2", false},
+		{"1.2.9", ">= 1.3", false},
+		{"1.3.0-rc.1", ">= 1.3", false},
+		{"1.3.0-rc.2", "> 1.3.0-rc.1", true},
+		{"1.3.0-rc.10", "> 1.3.0-rc.2", true},
+		{"1.3.0-alpha.1", "> 1.3.0-alpha", true},
+		{"1.3.0-alpha", "< 1.3.0-alpha.1", true},
+		{"1.3.0-alpha.beta", "> 1.3.0-alpha.1", true},
+		{"1.3.0+build", "1.3", true},
+		{"1.3.1", "!= 1.3.1", false},
+		{"1.3.1", "<= 1.3.1", true},
+	}
+	for _, test := range tests {
+		got, err := satisfies(test.version, test.constraint)
+		require.NoError(t, err)
+		require.Equal(t, test.want, got, "%s %s", test.version, test.constraint)
+	}
+
+	_, err := satisfies("1.x", ">= 1")
+	require.EqualError(t, err, `invalid version "1.x"`)
+	_, err = satisfies("1.0", "=> 1")
+	require.EqualError(t, err, `invalid constraint operator "=>"`)
+}
+
+func TestPrepareBundles_constraints(t *testing.T) {
+	session := Bundle{Name: "session", Version: "1.2.0"}
+	auth := Bundle{
+		Name:        "auth",
+		Bundles:     []Bundle{session},
+		Constraints: []Constraint{{Bundle: "session", Version: ">= 1.3"}},
+	}
+
+	t.Run("incompatible version causes error", func(t *testing.T) {
+		_, err := prepareBundles([]Bundle{auth}, Info{}, nil)
+		require.EqualError(t, err, "bundle auth requires session >= 1.3, got 1.2.0")
+	})
+
+	t.Run("compatible version", func(t *testing.T) {
+		auth := auth
+		auth.Bundles = []Bundle{{Name: "session", Version: "1.3.1"}}
+		_, err := prepareBundles([]Bundle{auth}, Info{}, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("constraint of absent bundle ignored", func(t *testing.T) {
+		auth := auth
+		auth.Bundles = nil
+		_, err := prepareBundles([]Bundle{auth}, Info{}, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("bundle without version causes error", func(t *testing.T) {
+		auth := auth
+		auth.Bundles = []Bundle{{Name: "session"}}
+		_, err := prepareBundles([]Bundle{auth}, Info{}, nil)
+		require.EqualError(t, err, "bundle auth requires session >= 1.3: session has no version")
+	})
+}